@@ -0,0 +1,133 @@
+package chatwootclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type AgentBot struct {
+	ID          int    `json:"id"`
+	AccountID   int    `json:"account_id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	OutgoingUrl string `json:"outgoing_url,omitempty"`
+	BotType     string `json:"bot_type,omitempty"`
+	Thumbnail   string `json:"thumbnail,omitempty"`
+	AccessToken string `json:"access_token,omitempty"`
+}
+
+// CreateAgentBotRequest creates an agent bot whose callbacks are sent to the outgoing url. The avatar is downloaded
+// by Chatwoot from AvatarUrl.
+type CreateAgentBotRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	OutgoingUrl string `json:"outgoing_url"`
+	AvatarUrl   string `json:"avatar_url,omitempty"`
+	BotType     string `json:"bot_type,omitempty"`
+}
+
+// UpdateAgentBotRequest only changes the fields that are set.
+type UpdateAgentBotRequest struct {
+	Name        string  `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	OutgoingUrl string  `json:"outgoing_url,omitempty"`
+	AvatarUrl   string  `json:"avatar_url,omitempty"`
+}
+
+// ListAgentBots returns the agent bots of the account. Agent bots of the installation that are available to all
+// accounts are included, their AccountID is 0.
+func (client *ChatwootClient) ListAgentBots(accountId int64, agentToken string) ([]AgentBot, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agent_bots", client.BaseUrl, accountId)
+
+	var agentBots []AgentBot
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &agentBots); err != nil {
+		return nil, err
+	}
+
+	return agentBots, nil
+}
+
+func (client *ChatwootClient) GetAgentBot(accountId int64, agentBotId int64, agentToken string) (AgentBot, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agent_bots/%v", client.BaseUrl, accountId, agentBotId)
+
+	var agentBot AgentBot
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &agentBot); err != nil {
+		return AgentBot{}, err
+	}
+
+	return agentBot, nil
+}
+
+// CreateAgentBot creates an agent bot and returns it including its access token.
+func (client *ChatwootClient) CreateAgentBot(accountId int64, agentToken string, createAgentBotRequest CreateAgentBotRequest) (AgentBot, error) {
+
+	if agentToken == "" {
+		return AgentBot{}, errors.New("agentToken is empty. Creating agent bots requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agent_bots", client.BaseUrl, accountId)
+
+	var agentBot AgentBot
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, createAgentBotRequest, &agentBot); err != nil {
+		return AgentBot{}, err
+	}
+
+	return agentBot, nil
+}
+
+func (client *ChatwootClient) UpdateAgentBot(accountId int64, agentBotId int64, agentToken string, updateAgentBotRequest UpdateAgentBotRequest) (AgentBot, error) {
+
+	if agentToken == "" {
+		return AgentBot{}, errors.New("agentToken is empty. Updating agent bots requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agent_bots/%v", client.BaseUrl, accountId, agentBotId)
+
+	var agentBot AgentBot
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, updateAgentBotRequest, &agentBot); err != nil {
+		return AgentBot{}, err
+	}
+
+	return agentBot, nil
+}
+
+func (client *ChatwootClient) DeleteAgentBot(accountId int64, agentBotId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting agent bots requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agent_bots/%v", client.BaseUrl, accountId, agentBotId)
+
+	return client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil)
+}
+
+// ResetAgentBotAccessToken invalidates the access token of the agent bot and returns the new one. Requests using the
+// previous token fail from now on, e.g. the AgentBotToken of a running bot has to be replaced.
+func (client *ChatwootClient) ResetAgentBotAccessToken(accountId int64, agentBotId int64, agentToken string) (string, error) {
+
+	if agentToken == "" {
+		return "", errors.New("agentToken is empty. Resetting agent bot access tokens requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agent_bots/%v/reset_access_token", client.BaseUrl, accountId, agentBotId)
+
+	var agentBot AgentBot
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, nil, &agentBot); err != nil {
+		return "", err
+	}
+
+	if agentBot.AccessToken == "" {
+		return "", errors.New("response contains no access token")
+	}
+
+	return agentBot.AccessToken, nil
+}
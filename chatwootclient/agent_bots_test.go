@@ -0,0 +1,74 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAgentBots(t *testing.T) {
+
+	var createAgentBotRequest CreateAgentBotRequest
+	var updateAgentBotRequest map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v1/accounts/1/agent_bots":
+			json.NewDecoder(r.Body).Decode(&createAgentBotRequest)
+			w.Write([]byte(`{"id": 4, "account_id": 1, "name": "Support Bot", "access_token": "first"}`))
+		case "PATCH /api/v1/accounts/1/agent_bots/4":
+			json.NewDecoder(r.Body).Decode(&updateAgentBotRequest)
+			w.Write([]byte(`{"id": 4, "account_id": 1, "name": "Support Bot", "description": ""}`))
+		case "POST /api/v1/accounts/1/agent_bots/4/reset_access_token":
+			w.Write([]byte(`{"id": 4, "account_id": 1, "name": "Support Bot", "access_token": "second"}`))
+		case "DELETE /api/v1/accounts/1/agent_bots/4":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	agentBot, err := client.CreateAgentBot(1, "agent-token", CreateAgentBotRequest{
+		Name:        "Support Bot",
+		OutgoingUrl: "https://bots.example.com/support",
+		AvatarUrl:   "https://bots.example.com/support.png",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if agentBot.ID != 4 || agentBot.AccessToken != "first" || createAgentBotRequest.OutgoingUrl != "https://bots.example.com/support" {
+		t.Fatalf("unexpected agent bot: %+v, request: %+v", agentBot, createAgentBotRequest)
+	}
+
+	if _, err := client.UpdateAgentBot(1, 4, "agent-token", UpdateAgentBotRequest{Description: String("")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if description, ok := updateAgentBotRequest["description"]; !ok || description != "" || len(updateAgentBotRequest) != 1 {
+		t.Fatalf("expected only the description to be cleared: %v", updateAgentBotRequest)
+	}
+
+	if accessToken, err := client.ResetAgentBotAccessToken(1, 4, "agent-token"); err != nil || accessToken != "second" {
+		t.Fatalf("unexpected access token %q: %v", accessToken, err)
+	}
+
+	if err := client.DeleteAgentBot(1, 4, "agent-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteAgentBot(1, 4, ""); err == nil {
+		t.Fatal("expected an error without agent token")
+	}
+
+}
@@ -0,0 +1,136 @@
+package chatwootclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	AgentRoleAgent         = "agent"
+	AgentRoleAdministrator = "administrator"
+)
+
+const (
+	AvailabilityOnline  = "online"
+	AvailabilityBusy    = "busy"
+	AvailabilityOffline = "offline"
+)
+
+// Agent is a user of the account, e.g. an inbox or team member.
+type Agent struct {
+	ID                 int    `json:"id"`
+	AccountID          int    `json:"account_id,omitempty"`
+	Name               string `json:"name"`
+	AvailableName      string `json:"available_name,omitempty"`
+	Email              string `json:"email"`
+	Role               string `json:"role,omitempty"`
+	AvailabilityStatus string `json:"availability_status,omitempty"`
+	AutoOffline        bool   `json:"auto_offline"`
+	Confirmed          bool   `json:"confirmed"`
+	Thumbnail          string `json:"thumbnail,omitempty"`
+}
+
+// Online reports whether the agent is currently available for conversations.
+func (agent Agent) Online() bool {
+	return agent.AvailabilityStatus == AvailabilityOnline
+}
+
+// OnlineAgents returns the agents that are currently online.
+func OnlineAgents(agents []Agent) []Agent {
+
+	var onlineAgents []Agent
+
+	for _, agent := range agents {
+		if agent.Online() {
+			onlineAgents = append(onlineAgents, agent)
+		}
+	}
+
+	return onlineAgents
+}
+
+type CreateAgentRequest struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Availability string `json:"availability,omitempty"`
+	AutoOffline  *bool  `json:"auto_offline,omitempty"`
+}
+
+// UpdateAgentRequest only changes the fields that are set.
+type UpdateAgentRequest struct {
+	Role         string `json:"role,omitempty"`
+	Availability string `json:"availability,omitempty"`
+	AutoOffline  *bool  `json:"auto_offline,omitempty"`
+}
+
+func (client *ChatwootClient) ListAgents(accountId int64, agentToken string) ([]Agent, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agents", client.BaseUrl, accountId)
+
+	var agents []Agent
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &agents); err != nil {
+		return nil, err
+	}
+
+	return agents, nil
+}
+
+// ListOnlineAgents returns the agents of the account that are currently online, e.g. to pick an assignee.
+func (client *ChatwootClient) ListOnlineAgents(accountId int64, agentToken string) ([]Agent, error) {
+
+	agents, err := client.ListAgents(accountId, agentToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return OnlineAgents(agents), nil
+}
+
+func (client *ChatwootClient) CreateAgent(accountId int64, agentToken string, createAgentRequest CreateAgentRequest) (Agent, error) {
+
+	if agentToken == "" {
+		return Agent{}, errors.New("agentToken is empty. Creating agents requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agents", client.BaseUrl, accountId)
+
+	var agent Agent
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, createAgentRequest, &agent); err != nil {
+		return Agent{}, err
+	}
+
+	return agent, nil
+}
+
+func (client *ChatwootClient) UpdateAgent(accountId int64, agentId int64, agentToken string, updateAgentRequest UpdateAgentRequest) (Agent, error) {
+
+	if agentToken == "" {
+		return Agent{}, errors.New("agentToken is empty. Updating agents requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agents/%v", client.BaseUrl, accountId, agentId)
+
+	var agent Agent
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, updateAgentRequest, &agent); err != nil {
+		return Agent{}, err
+	}
+
+	return agent, nil
+}
+
+func (client *ChatwootClient) DeleteAgent(accountId int64, agentId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting agents requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/agents/%v", client.BaseUrl, accountId, agentId)
+
+	return client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil)
+}
@@ -0,0 +1,83 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListOnlineAgents(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path != "/api/v1/accounts/1/agents" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`[{"id": 1, "name": "Jane", "availability_status": "online"}, {"id": 2, "name": "John", "availability_status": "busy"},
+			{"id": 3, "name": "Joe", "availability_status": "offline"}, {"id": 4, "name": "Jim", "availability_status": "online"}]`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	agents, err := client.ListOnlineAgents(1, "agent-token")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(agents) != 2 || agents[0].ID != 1 || agents[1].ID != 4 {
+		t.Fatalf("unexpected agents: %+v", agents)
+	}
+
+}
+
+func TestUpdateAgent(t *testing.T) {
+
+	var updateAgentRequest map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v1/accounts/1/agents/2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(&updateAgentRequest)
+
+		w.Write([]byte(`{"id": 2, "name": "John", "role": "administrator", "availability_status": "busy", "auto_offline": false}`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	agent, err := client.UpdateAgent(1, 2, "agent-token", UpdateAgentRequest{
+		Role:         AgentRoleAdministrator,
+		Availability: AvailabilityBusy,
+		AutoOffline:  Bool(false),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updateAgentRequest["auto_offline"] != false || updateAgentRequest["availability"] != "busy" || updateAgentRequest["role"] != "administrator" {
+		t.Fatalf("unexpected request: %v", updateAgentRequest)
+	}
+
+	if agent.Role != AgentRoleAdministrator || agent.Online() {
+		t.Fatalf("unexpected agent: %+v", agent)
+	}
+
+}
@@ -0,0 +1,248 @@
+package chatwootclient
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxAttachmentSize is the maximum size of an attachment accepted by Chatwoot.
+const DefaultMaxAttachmentSize int64 = 40 << 20
+
+// DefaultAllowedContentTypes are the attachment types accepted by Chatwoot. Entries ending with /* match all subtypes.
+var DefaultAllowedContentTypes = []string{
+	"image/*",
+	"audio/*",
+	"video/*",
+	"text/csv",
+	"text/plain",
+	"text/rtf",
+	"application/json",
+	"application/pdf",
+	"application/rtf",
+	"application/zip",
+	"application/x-7z-compressed",
+	"application/vnd.rar",
+	"application/x-tar",
+	"application/msword",
+	"application/vnd.ms-excel",
+	"application/vnd.ms-powerpoint",
+	"application/vnd.oasis.opendocument.text",
+	"application/vnd.openxmlformats-officedocument.*",
+}
+
+type AttachmentErrorReason string
+
+const (
+	AttachmentTooLarge               AttachmentErrorReason = "too_large"
+	AttachmentContentTypeMismatch    AttachmentErrorReason = "content_type_mismatch"
+	AttachmentContentTypeNotAllowed  AttachmentErrorReason = "content_type_not_allowed"
+	AttachmentTranscodingUnsupported AttachmentErrorReason = "transcoding_unsupported"
+)
+
+// AttachmentError is returned when an attachment is rejected before or while it is uploaded.
+type AttachmentError struct {
+	Filename            string
+	Reason              AttachmentErrorReason
+	ContentType         string // the declared content type
+	DetectedContentType string // the content type sniffed from the content
+	MaxSize             int64
+}
+
+func (attachmentError *AttachmentError) Error() string {
+	switch attachmentError.Reason {
+	case AttachmentTooLarge:
+		return fmt.Sprintf("%s: %s (%d bytes)", attachmentError.Filename, ErrAttachmentTooLarge, attachmentError.MaxSize)
+	case AttachmentContentTypeMismatch:
+		return fmt.Sprintf("%s: declared content type %s does not match the detected content type %s", attachmentError.Filename, attachmentError.ContentType, attachmentError.DetectedContentType)
+	case AttachmentTranscodingUnsupported:
+		return fmt.Sprintf("%s: content type %s is not allowed and cannot be transcoded", attachmentError.Filename, attachmentError.DetectedContentType)
+	default:
+		return fmt.Sprintf("%s: content type %s is not allowed", attachmentError.Filename, attachmentError.DetectedContentType)
+	}
+}
+
+// Is allows to check for oversized attachments using errors.Is(err, ErrAttachmentTooLarge).
+func (attachmentError *AttachmentError) Is(target error) bool {
+	return target == ErrAttachmentTooLarge && attachmentError.Reason == AttachmentTooLarge
+}
+
+// mediaType returns the content type without parameters like the charset.
+func mediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+func contentTypeAllowed(contentType string, allowedContentTypes []string) bool {
+	for _, allowed := range allowedContentTypes {
+		if allowed == contentType || (strings.HasSuffix(allowed, "*") && strings.HasPrefix(contentType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageTypeAliases maps non standard image types that are in use to the types returned by http.DetectContentType.
+var imageTypeAliases = map[string]string{
+	"image/jpg":                "image/jpeg",
+	"image/pjpeg":              "image/jpeg",
+	"image/x-png":              "image/png",
+	"image/vnd.microsoft.icon": "image/x-icon",
+}
+
+// contentTypeMismatch reports whether the sniffed content type contradicts the declared one. http.DetectContentType
+// only knows a limited set of signatures, so only contradictions that are certain are reported, e.g. an HTML error
+// page served as image or a PNG declared as JPEG.
+func contentTypeMismatch(declared string, detected string) bool {
+
+	if alias, ok := imageTypeAliases[declared]; ok {
+		declared = alias
+	}
+
+	if declared == "" || declared == "application/octet-stream" || detected == "application/octet-stream" || declared == detected {
+		return false
+	}
+
+	// SVG images are XML documents
+	if declared == "image/svg+xml" && (detected == "text/xml" || detected == "text/plain") {
+		return false
+	}
+
+	if detected == "text/html" || detected == "text/xml" {
+		return true
+	}
+
+	declaredType, _, _ := strings.Cut(declared, "/")
+	detectedType, _, _ := strings.Cut(detected, "/")
+
+	switch declaredType {
+	case "image":
+		// the signatures of images are certain, so any other detected type is a contradiction
+		return true
+	case "audio", "video":
+		// containers like mp4 and ogg are used for audio and video
+		return detectedType != "audio" && detectedType != "video" && detected != "application/ogg"
+	}
+
+	return false
+}
+
+// prepareAttachment sniffs the content of the attachment and returns the content type to upload it with. Attachments
+// with a content type that is not allowed are transcoded to PNG or JPEG when requested and possible, which requires
+// the image to be decoded in memory.
+func (sendAttachmentsMessageRequest SendAttachmentsMessageRequest) prepareAttachment(content io.Reader, filename string, contentType string) (io.Reader, string, string, error) {
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, "", "", err
+	}
+	head = head[:n]
+
+	content = io.MultiReader(bytes.NewReader(head), content)
+
+	if sendAttachmentsMessageRequest.SkipValidation {
+		return content, filename, contentType, nil
+	}
+
+	declared := mediaType(contentType)
+	detected := mediaType(http.DetectContentType(head))
+
+	if contentTypeMismatch(declared, detected) {
+		return nil, "", "", &AttachmentError{
+			Filename:            filename,
+			Reason:              AttachmentContentTypeMismatch,
+			ContentType:         declared,
+			DetectedContentType: detected,
+		}
+	}
+
+	// the sniffed type of images is certain, see contentTypeMismatch
+	effective := declared
+	if effective == "" || effective == "application/octet-stream" || strings.HasPrefix(detected, "image/") {
+		effective = detected
+	}
+
+	allowedContentTypes := sendAttachmentsMessageRequest.AllowedContentTypes
+	if allowedContentTypes == nil {
+		allowedContentTypes = DefaultAllowedContentTypes
+	}
+
+	if contentTypeAllowed(effective, allowedContentTypes) {
+		return content, filename, effective, nil
+	}
+
+	attachmentError := &AttachmentError{
+		Filename:            filename,
+		Reason:              AttachmentContentTypeNotAllowed,
+		ContentType:         declared,
+		DetectedContentType: effective,
+	}
+
+	if !sendAttachmentsMessageRequest.TranscodeImages || !strings.HasPrefix(effective, "image/") {
+		return nil, "", "", attachmentError
+	}
+
+	return transcodeImage(content, filename, detected, allowedContentTypes, sendAttachmentsMessageRequest.maxAttachmentSize(), attachmentError)
+}
+
+// transcodeImage decodes the image based on the detected content type and encodes it as PNG or JPEG.
+func transcodeImage(content io.Reader, filename string, detected string, allowedContentTypes []string, maxAttachmentSize int64, attachmentError *AttachmentError) (io.Reader, string, string, error) {
+
+	var encode func(io.Writer, image.Image) error
+	var contentType, extension string
+
+	switch {
+	case contentTypeAllowed("image/png", allowedContentTypes):
+		encode, contentType, extension = png.Encode, "image/png", ".png"
+	case contentTypeAllowed("image/jpeg", allowedContentTypes):
+		encode = func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: 90})
+		}
+		contentType, extension = "image/jpeg", ".jpg"
+	default:
+		attachmentError.Reason = AttachmentTranscodingUnsupported
+		return nil, "", "", attachmentError
+	}
+
+	var decode func(io.Reader) (image.Image, error)
+
+	switch detected {
+	case "image/gif":
+		decode = gif.Decode
+	case "image/jpeg":
+		decode = jpeg.Decode
+	case "image/png":
+		decode = png.Decode
+	default:
+		attachmentError.Reason = AttachmentTranscodingUnsupported
+		return nil, "", "", attachmentError
+	}
+
+	if maxAttachmentSize > 0 {
+		content = &limitedReader{reader: content, filename: filename, max: maxAttachmentSize}
+	}
+
+	img, err := decode(content)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	var buf bytes.Buffer
+
+	if err := encode(&buf, img); err != nil {
+		return nil, "", "", err
+	}
+
+	return &buf, strings.TrimSuffix(filename, filepath.Ext(filename)) + extension, contentType, nil
+}
@@ -0,0 +1,167 @@
+package chatwootclient
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendAttachmentsMessageRejectsMismatchingContent(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path == "/remote/product.jpg" {
+			// CDN error page served with status 200
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("<!DOCTYPE html><html><body>Not Found</body></html>"))
+			return
+		}
+
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"id": 7}`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	_, err := client.SendImageMessage(1, 2, "", server.URL+"/remote/product.jpg", "")
+
+	var attachmentError *AttachmentError
+
+	if !errors.As(err, &attachmentError) || attachmentError.Reason != AttachmentContentTypeMismatch {
+		t.Fatalf("expected a content type mismatch, got %v", err)
+	}
+
+	if attachmentError.DetectedContentType != "text/html" {
+		t.Fatalf("unexpected detected content type %s", attachmentError.DetectedContentType)
+	}
+
+	_, err = client.SendAttachmentsMessage(1, 2, "", SendAttachmentsMessageRequest{
+		Attachments: []Attachment{
+			AttachmentFromBytes("page.html", "", []byte("<html><body>hello</body></html>")),
+		},
+	})
+
+	if !errors.As(err, &attachmentError) || attachmentError.Reason != AttachmentContentTypeNotAllowed {
+		t.Fatalf("expected a content type that is not allowed, got %v", err)
+	}
+
+	var pngImage bytes.Buffer
+	png.Encode(&pngImage, image.NewGray(image.Rect(0, 0, 4, 4)))
+
+	_, err = client.SendAttachmentsMessage(1, 2, "", SendAttachmentsMessageRequest{
+		Attachments: []Attachment{
+			AttachmentFromBytes("photo.jpg", "image/jpeg", pngImage.Bytes()),
+		},
+	})
+
+	if !errors.As(err, &attachmentError) || attachmentError.Reason != AttachmentContentTypeMismatch || attachmentError.DetectedContentType != "image/png" {
+		t.Fatalf("expected a content type mismatch, got %v", err)
+	}
+
+	_, err = client.SendAttachmentsMessage(1, 2, "", SendAttachmentsMessageRequest{
+		Attachments: []Attachment{
+			AttachmentFromBytes("photo.png", "image/x-png", pngImage.Bytes()),
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+}
+
+func TestSendAttachmentsMessageTranscodesImages(t *testing.T) {
+
+	var uploadedContentType, uploadedFilename string
+	var uploaded []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		reader := multipart.NewReader(r.Body, params["boundary"])
+
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if part.FileName() != "" {
+				uploadedFilename = part.FileName()
+				uploadedContentType = part.Header.Get("Content-Type")
+				uploaded, _ = io.ReadAll(part)
+			}
+		}
+
+		w.Write([]byte(`{"id": 7}`))
+
+	}))
+
+	defer server.Close()
+
+	var gifImage bytes.Buffer
+	gif.Encode(&gifImage, image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{color.Black, color.White}), nil)
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	request := SendAttachmentsMessageRequest{
+		Attachments: []Attachment{
+			AttachmentFromBytes("animation.gif", "image/gif", gifImage.Bytes()),
+		},
+		AllowedContentTypes: []string{"image/jpeg", "image/png"},
+	}
+
+	_, err := client.SendAttachmentsMessage(1, 2, "", request)
+
+	var attachmentError *AttachmentError
+
+	if !errors.As(err, &attachmentError) || attachmentError.Reason != AttachmentContentTypeNotAllowed {
+		t.Fatalf("expected a content type that is not allowed, got %v", err)
+	}
+
+	request.TranscodeImages = true
+
+	if _, err := client.SendAttachmentsMessage(1, 2, "", request); err != nil {
+		t.Fatal(err)
+	}
+
+	if uploadedFilename != "animation.png" || uploadedContentType != "image/png" {
+		t.Fatalf("unexpected upload %s (%s)", uploadedFilename, uploadedContentType)
+	}
+
+	if _, err := png.Decode(bytes.NewReader(uploaded)); err != nil {
+		t.Fatalf("uploaded image is not a PNG: %v", err)
+	}
+
+	// the decoder is chosen by the detected content type
+	var jpegImage bytes.Buffer
+	jpeg.Encode(&jpegImage, image.NewGray(image.Rect(0, 0, 4, 4)), nil)
+
+	request.Attachments = []Attachment{AttachmentFromBytes("photo.jpg", "image/jpg", jpegImage.Bytes())}
+	request.AllowedContentTypes = []string{"image/png"}
+
+	if _, err := client.SendAttachmentsMessage(1, 2, "", request); err != nil {
+		t.Fatal(err)
+	}
+
+	if uploadedFilename != "photo.png" || uploadedContentType != "image/png" {
+		t.Fatalf("unexpected upload %s (%s)", uploadedFilename, uploadedContentType)
+	}
+
+}
@@ -0,0 +1,349 @@
+package chatwootclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attachment is a file that is uploaded together with a message. Exactly one of Reader, Path, Data and URL has to be
+// set. Filename and ContentType are derived from the source when they are empty.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Reader      io.Reader
+	Path        string
+	Data        []byte
+	URL         string
+}
+
+func AttachmentFromReader(filename string, contentType string, reader io.Reader) Attachment {
+	return Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Reader:      reader,
+	}
+}
+
+func AttachmentFromFile(filePath string) Attachment {
+	return Attachment{
+		Path: filePath,
+	}
+}
+
+func AttachmentFromBytes(filename string, contentType string, data []byte) Attachment {
+	return Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Data:        data,
+	}
+}
+
+func AttachmentFromURL(attachmentUrl string) Attachment {
+	return Attachment{
+		URL: attachmentUrl,
+	}
+}
+
+// open returns the content of the attachment together with the filename and MIME type that are used for the upload.
+// Downloads of URL attachments are aborted when the context is done.
+func (attachment Attachment) open(ctx context.Context) (io.ReadCloser, string, string, error) {
+
+	filename := attachment.Filename
+	contentType := attachment.ContentType
+
+	var content io.ReadCloser
+
+	switch {
+	case attachment.Reader != nil:
+		content = io.NopCloser(attachment.Reader)
+
+	case attachment.Path != "":
+		file, err := os.Open(attachment.Path)
+		if err != nil {
+			return nil, "", "", err
+		}
+		content = file
+		if filename == "" {
+			filename = filepath.Base(attachment.Path)
+		}
+
+	case attachment.Data != nil:
+		content = io.NopCloser(bytes.NewReader(attachment.Data))
+
+	case attachment.URL != "":
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, attachment.URL, nil)
+		if err != nil {
+			return nil, "", "", err
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if response.StatusCode != http.StatusOK {
+			response.Body.Close()
+			return nil, "", "", fmt.Errorf("failed to download attachment %s: %s", attachment.URL, response.Status)
+		}
+		content = response.Body
+		if filename == "" {
+			if u, err := url.Parse(attachment.URL); err == nil {
+				filename = path.Base(u.Path)
+			}
+		}
+		if contentType == "" {
+			contentType = response.Header.Get("Content-Type")
+		}
+
+	default:
+		return nil, "", "", errors.New("attachment has no content")
+	}
+
+	if filename == "" || filename == "/" || filename == "." {
+		filename = "attachment"
+	}
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return content, filename, contentType, nil
+}
+
+// SendAttachmentsMessageRequest describes a message with one or more attachments. MessageType defaults to outgoing.
+//
+// MaxAttachmentSize limits the size of every single attachment in bytes. It defaults to DefaultMaxAttachmentSize, a
+// negative value disables the limit. The limit is enforced while streaming, so the upload is aborted as soon as an
+// attachment exceeds it. Progress is called with the number of bytes of the attachment that have been uploaded so far.
+//
+// DownloadTimeout limits the time to download and upload a URL attachment. It defaults to
+// DefaultAttachmentDownloadTimeout, a negative value disables the timeout.
+//
+// The content of every attachment is sniffed before the upload. Attachments whose content contradicts the declared
+// content type or whose type is not in AllowedContentTypes (DefaultAllowedContentTypes if nil) are rejected with an
+// *AttachmentError. With TranscodeImages, GIF, JPEG and PNG images that are not allowed are converted to an allowed
+// PNG or JPEG instead, e.g. to send GIFs to channels that only accept JPEG and PNG.
+type SendAttachmentsMessageRequest struct {
+	Content             string
+	MessageType         string
+	Private             bool
+	Attachments         []Attachment
+	MaxAttachmentSize   int64
+	DownloadTimeout     time.Duration
+	Progress            func(filename string, uploaded int64)
+	AllowedContentTypes []string
+	TranscodeImages     bool
+	SkipValidation      bool
+}
+
+func (sendAttachmentsMessageRequest SendAttachmentsMessageRequest) maxAttachmentSize() int64 {
+	if sendAttachmentsMessageRequest.MaxAttachmentSize == 0 {
+		return DefaultMaxAttachmentSize
+	}
+	return sendAttachmentsMessageRequest.MaxAttachmentSize
+}
+
+// DefaultAttachmentDownloadTimeout is the time a URL attachment may take to be downloaded and uploaded.
+const DefaultAttachmentDownloadTimeout = 2 * time.Minute
+
+// downloadContext returns the context that limits the download of a URL attachment.
+func (sendAttachmentsMessageRequest SendAttachmentsMessageRequest) downloadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+
+	switch {
+	case sendAttachmentsMessageRequest.DownloadTimeout < 0:
+		return context.WithCancel(ctx)
+	case sendAttachmentsMessageRequest.DownloadTimeout == 0:
+		return context.WithTimeout(ctx, DefaultAttachmentDownloadTimeout)
+	}
+
+	return context.WithTimeout(ctx, sendAttachmentsMessageRequest.DownloadTimeout)
+}
+
+var ErrAttachmentTooLarge = errors.New("attachment exceeds the maximum attachment size")
+
+var errRequestFinished = errors.New("request finished")
+
+// limitedReader fails with an *AttachmentError as soon as more than max bytes are read and reports the progress.
+type limitedReader struct {
+	reader   io.Reader
+	filename string
+	max      int64
+	read     int64
+	progress func(filename string, uploaded int64)
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+
+	n, err := l.reader.Read(p)
+	l.read += int64(n)
+
+	if l.max > 0 && l.read > l.max {
+		return 0, &AttachmentError{
+			Filename: l.filename,
+			Reason:   AttachmentTooLarge,
+			MaxSize:  l.max,
+		}
+	}
+
+	if n > 0 && l.progress != nil {
+		l.progress(l.filename, l.read)
+	}
+
+	return n, err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeAttachmentsMessage writes the message fields and all attachments as multipart form to the writer.
+func writeAttachmentsMessage(ctx context.Context, mw *multipart.Writer, sendAttachmentsMessageRequest SendAttachmentsMessageRequest) error {
+
+	messageType := sendAttachmentsMessageRequest.MessageType
+	if messageType == "" {
+		messageType = "outgoing"
+	}
+
+	if sendAttachmentsMessageRequest.Content != "" {
+		if err := mw.WriteField("content", sendAttachmentsMessageRequest.Content); err != nil {
+			return err
+		}
+	}
+
+	if err := mw.WriteField("message_type", messageType); err != nil {
+		return err
+	}
+
+	if err := mw.WriteField("private", strconv.FormatBool(sendAttachmentsMessageRequest.Private)); err != nil {
+		return err
+	}
+
+	for _, attachment := range sendAttachmentsMessageRequest.Attachments {
+		if err := sendAttachmentsMessageRequest.writeAttachment(ctx, mw, attachment); err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+func (sendAttachmentsMessageRequest SendAttachmentsMessageRequest) writeAttachment(ctx context.Context, mw *multipart.Writer, attachment Attachment) error {
+
+	ctx, cancel := sendAttachmentsMessageRequest.downloadContext(ctx)
+	defer cancel()
+
+	source, filename, contentType, err := attachment.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	content, filename, contentType, err := sendAttachmentsMessageRequest.prepareAttachment(source, filename, contentType)
+	if err != nil {
+		return err
+	}
+
+	partHeaders := make(textproto.MIMEHeader)
+	partHeaders.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachments[]"; filename="%s"`, quoteEscaper.Replace(filename)))
+	partHeaders.Set("Content-Type", contentType)
+
+	part, err := mw.CreatePart(partHeaders)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, &limitedReader{
+		reader:   content,
+		filename: filename,
+		max:      sendAttachmentsMessageRequest.maxAttachmentSize(),
+		progress: sendAttachmentsMessageRequest.Progress,
+	})
+
+	return err
+}
+
+// SendAttachmentsMessage creates a message with the given attachments. Attachments can be any mix of readers, local
+// files, in memory data and remote URLs.
+func (client *ChatwootClient) SendAttachmentsMessage(accountId int64, conversationId int64, agentBotToken string, sendAttachmentsMessageRequest SendAttachmentsMessageRequest) (CreateNewMessageResponse, error) {
+
+	if len(sendAttachmentsMessageRequest.Attachments) == 0 {
+		return CreateNewMessageResponse{}, errors.New("at least one attachment is required")
+	}
+
+	apiUrl := fmt.Sprintf("%s/api/v1/accounts/%d/conversations/%d/messages", client.BaseUrl, accountId, conversationId)
+
+	// the multipart body is streamed from the attachment sources to Chatwoot, so the attachments are never held in memory
+	pipeReader, pipeWriter := io.Pipe()
+
+	// the multipart writer uses a random boundary
+	mw := multipart.NewWriter(pipeWriter)
+
+	// cancels pending downloads of URL attachments once the request ended
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	writeErr := make(chan error, 1)
+
+	go func() {
+		err := writeAttachmentsMessage(ctx, mw, sendAttachmentsMessageRequest)
+		writeErr <- err
+		pipeWriter.CloseWithError(err)
+	}()
+
+	request, err := http.NewRequest(http.MethodPost, apiUrl, pipeReader)
+	if err != nil {
+		pipeReader.CloseWithError(err)
+		return CreateNewMessageResponse{}, err
+	}
+
+	request.Header.Set("Content-Type", mw.FormDataContentType())
+	request.Header.Add("api_access_token", agentBotToken)
+
+	response, err := http.DefaultClient.Do(request)
+
+	// unblocks the writer in case the request ended before the whole body was consumed, the writer returns soon after
+	// as it can neither write to the pipe nor continue a download
+	pipeReader.CloseWithError(errRequestFinished)
+	cancel()
+
+	if err != nil {
+		// errors of the attachment sources like an *AttachmentError are more meaningful than the transport error
+		if writeError := <-writeErr; writeError != nil && !errors.Is(writeError, errRequestFinished) && !errors.Is(writeError, io.ErrClosedPipe) {
+			return CreateNewMessageResponse{}, writeError
+		}
+		return CreateNewMessageResponse{}, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return CreateNewMessageResponse{}, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return CreateNewMessageResponse{}, fmt.Errorf("request failed: %s - Response body: %s", response.Status, string(body))
+	}
+
+	var createNewMessageResponse CreateNewMessageResponse
+
+	if err := json.Unmarshal(body, &createNewMessageResponse); err != nil {
+		return CreateNewMessageResponse{}, err
+	}
+
+	return createNewMessageResponse, nil
+}
@@ -0,0 +1,245 @@
+package chatwootclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendAttachmentsMessage(t *testing.T) {
+
+	type part struct {
+		filename    string
+		contentType string
+		content     string
+	}
+
+	var fields = map[string]string{}
+	var parts []part
+	var boundary string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path == "/remote/logo.png" {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG\x0D\x0A\x1A\x0Aremote"))
+			return
+		}
+
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		boundary = params["boundary"]
+
+		reader := multipart.NewReader(r.Body, boundary)
+
+		for {
+			p, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			content, _ := io.ReadAll(p)
+			if p.FileName() == "" {
+				fields[p.FormName()] = string(content)
+				continue
+			}
+			parts = append(parts, part{p.FileName(), p.Header.Get("Content-Type"), string(content)})
+		}
+
+		w.Write([]byte(`{"id": 7, "private": true}`))
+
+	}))
+
+	defer server.Close()
+
+	filePath := filepath.Join(t.TempDir(), "invoice.pdf")
+	os.WriteFile(filePath, []byte("file"), 0o600)
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	response, err := client.SendAttachmentsMessage(1, 2, "", SendAttachmentsMessageRequest{
+		Content: "Your documents",
+		Private: true,
+		Attachments: []Attachment{
+			AttachmentFromReader("notes.txt", "text/plain", strings.NewReader("reader")),
+			AttachmentFromFile(filePath),
+			AttachmentFromBytes("data.bin", "", []byte("bytes")),
+			AttachmentFromURL(server.URL + "/remote/logo.png"),
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.ID != 7 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+
+	if boundary == "" || boundary == "----WebKitFormBoundary" {
+		t.Fatalf("expected a random boundary, got %q", boundary)
+	}
+
+	if fields["content"] != "Your documents" || fields["private"] != "true" || fields["message_type"] != "outgoing" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+
+	expected := []part{
+		{"notes.txt", "text/plain", "reader"},
+		{"invoice.pdf", "application/pdf", "file"},
+		{"data.bin", "text/plain", "bytes"},
+		{"logo.png", "image/png", "\x89PNG\x0D\x0A\x1A\x0Aremote"},
+	}
+
+	if len(parts) != len(expected) {
+		t.Fatalf("expected %d attachments, got %d", len(expected), len(parts))
+	}
+
+	for i := range expected {
+		if parts[i] != expected[i] {
+			t.Fatalf("unexpected attachment %d: %+v", i, parts[i])
+		}
+	}
+
+}
+
+func TestSendAttachmentsMessageMaxAttachmentSize(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		io.Copy(io.Discard, r.Body)
+
+		w.Write([]byte(`{"id": 7}`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	var uploaded int64
+
+	_, err := client.SendAttachmentsMessage(1, 2, "", SendAttachmentsMessageRequest{
+		Attachments: []Attachment{
+			AttachmentFromReader("video.mp4", "video/mp4", io.LimitReader(zeroReader{}, 10<<20)),
+		},
+		MaxAttachmentSize: 1 << 20,
+		Progress: func(filename string, n int64) {
+			uploaded = n
+		},
+	})
+
+	if !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+
+	if uploaded == 0 || uploaded > 1<<20 {
+		t.Fatalf("unexpected progress: %d", uploaded)
+	}
+
+	_, err = client.SendAttachmentsMessage(1, 2, "", SendAttachmentsMessageRequest{
+		Attachments: []Attachment{
+			AttachmentFromReader("video.mp4", "video/mp4", io.LimitReader(zeroReader{}, 2<<20)),
+		},
+		MaxAttachmentSize: 4 << 20,
+		Progress: func(filename string, n int64) {
+			uploaded = n
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uploaded != 2<<20 {
+		t.Fatalf("unexpected progress: %d", uploaded)
+	}
+
+}
+
+func TestSendAttachmentsMessageStalledDownload(t *testing.T) {
+
+	release := make(chan struct{})
+
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+
+	defer stalled.Close()
+	defer close(release)
+
+	// drops the connection without reading the body
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+
+	defer failing.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		io.Copy(io.Discard, r.Body)
+
+		w.Write([]byte(`{"id": 7}`))
+
+	}))
+
+	defer server.Close()
+
+	for _, baseUrl := range []string{failing.URL, server.URL} {
+
+		client := ChatwootClient{
+			BaseUrl: baseUrl,
+		}
+
+		done := make(chan error, 1)
+
+		go func() {
+			_, err := client.SendAttachmentsMessage(1, 2, "", SendAttachmentsMessageRequest{
+				Attachments: []Attachment{
+					// flushes the request to the server before the download stalls
+					AttachmentFromBytes("notes.txt", "text/plain", []byte(strings.Repeat("a", 1<<20))),
+					AttachmentFromURL(stalled.URL + "/image.png"),
+				},
+				DownloadTimeout: 200 * time.Millisecond,
+			})
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Fatal("expected an error for the stalled download")
+			}
+			if baseUrl == server.URL && !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected the download to time out, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("SendAttachmentsMessage is blocked by the stalled download")
+		}
+	}
+
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,485 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const (
+	FilterOperatorEqualTo        = "equal_to"
+	FilterOperatorNotEqualTo     = "not_equal_to"
+	FilterOperatorContains       = "contains"
+	FilterOperatorDoesNotContain = "does_not_contain"
+	FilterOperatorIsPresent      = "is_present"
+	FilterOperatorIsNotPresent   = "is_not_present"
+)
+
+const (
+	QueryOperatorAnd = "and"
+	QueryOperatorOr  = "or"
+)
+
+const (
+	ActionAssignAgent         = "assign_agent"
+	ActionAssignTeam          = "assign_team"
+	ActionAddLabel            = "add_label"
+	ActionRemoveLabel         = "remove_label"
+	ActionSendMessage         = "send_message"
+	ActionAddPrivateNote      = "add_private_note"
+	ActionSendEmailToTeam     = "send_email_to_team"
+	ActionSendEmailTranscript = "send_email_transcript"
+	ActionSendWebhookEvent    = "send_webhook_event"
+	ActionMuteConversation    = "mute_conversation"
+	ActionSnoozeConversation  = "snooze_conversation"
+	ActionResolveConversation = "resolve_conversation"
+	ActionChangePriority      = "change_priority"
+	ActionSendAttachment      = "send_attachment"
+)
+
+// AutomationCondition compares the attribute with the values. QueryOperator combines the condition with the next
+// one, it is empty for the last condition.
+type AutomationCondition struct {
+	AttributeKey   string        `json:"attribute_key"`
+	FilterOperator string        `json:"filter_operator"`
+	Values         []interface{} `json:"values"`
+	QueryOperator  string        `json:"query_operator,omitempty"`
+}
+
+// AutomationAction is an action of an automation rule or a macro. Use the constructors like AssignTeamAction for the
+// parameters expected by Chatwoot.
+type AutomationAction struct {
+	ActionName   string        `json:"action_name"`
+	ActionParams []interface{} `json:"action_params,omitempty"`
+}
+
+func AssignAgentAction(agentId int64) AutomationAction {
+	return AutomationAction{ActionName: ActionAssignAgent, ActionParams: []interface{}{agentId}}
+}
+
+func AssignTeamAction(teamId int64) AutomationAction {
+	return AutomationAction{ActionName: ActionAssignTeam, ActionParams: []interface{}{teamId}}
+}
+
+func AddLabelAction(labels ...string) AutomationAction {
+	return AutomationAction{ActionName: ActionAddLabel, ActionParams: stringParams(labels)}
+}
+
+func RemoveLabelAction(labels ...string) AutomationAction {
+	return AutomationAction{ActionName: ActionRemoveLabel, ActionParams: stringParams(labels)}
+}
+
+func SendMessageAction(content string) AutomationAction {
+	return AutomationAction{ActionName: ActionSendMessage, ActionParams: []interface{}{content}}
+}
+
+func AddPrivateNoteAction(content string) AutomationAction {
+	return AutomationAction{ActionName: ActionAddPrivateNote, ActionParams: []interface{}{content}}
+}
+
+// SendEmailToTeamAction sends the message by email to the members of the teams.
+func SendEmailToTeamAction(message string, teamIds ...int64) AutomationAction {
+	return AutomationAction{ActionName: ActionSendEmailToTeam, ActionParams: []interface{}{map[string]interface{}{
+		"message":  message,
+		"team_ids": teamIds,
+	}}}
+}
+
+func SendEmailTranscriptAction(email string) AutomationAction {
+	return AutomationAction{ActionName: ActionSendEmailTranscript, ActionParams: []interface{}{email}}
+}
+
+// SendWebhookEventAction posts the event to the url.
+func SendWebhookEventAction(url string) AutomationAction {
+	return AutomationAction{ActionName: ActionSendWebhookEvent, ActionParams: []interface{}{url}}
+}
+
+func MuteConversationAction() AutomationAction {
+	return AutomationAction{ActionName: ActionMuteConversation}
+}
+
+func SnoozeConversationAction() AutomationAction {
+	return AutomationAction{ActionName: ActionSnoozeConversation}
+}
+
+func ResolveConversationAction() AutomationAction {
+	return AutomationAction{ActionName: ActionResolveConversation}
+}
+
+// ChangePriorityAction sets the priority of the conversation: "urgent", "high", "medium", "low" or "none".
+func ChangePriorityAction(priority string) AutomationAction {
+	return AutomationAction{ActionName: ActionChangePriority, ActionParams: []interface{}{priority}}
+}
+
+// SendAttachmentAction sends the blob previously uploaded to Chatwoot.
+func SendAttachmentAction(blobId int64) AutomationAction {
+	return AutomationAction{ActionName: ActionSendAttachment, ActionParams: []interface{}{blobId}}
+}
+
+func stringParams(values []string) []interface{} {
+	params := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		params = append(params, value)
+	}
+	return params
+}
+
+type AutomationRule struct {
+	ID          int                   `json:"id"`
+	AccountID   int                   `json:"account_id,omitempty"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	EventName   WebhookEvent          `json:"event_name"`
+	Active      bool                  `json:"active"`
+	Conditions  []AutomationCondition `json:"conditions"`
+	Actions     []AutomationAction    `json:"actions"`
+	CreatedOn   Timestamp             `json:"created_on"`
+}
+
+// Request returns the request that creates the rule, e.g. to compare it with the desired rule.
+func (automationRule AutomationRule) Request() AutomationRuleRequest {
+	return AutomationRuleRequest{
+		Name:        automationRule.Name,
+		Description: automationRule.Description,
+		EventName:   automationRule.EventName,
+		Active:      automationRule.Active,
+		Conditions:  automationRule.Conditions,
+		Actions:     automationRule.Actions,
+	}
+}
+
+// AutomationRuleRequest creates or replaces an automation rule. Build it with NewAutomationRule:
+//
+//	NewAutomationRule("VIP routing", EventConversationCreated).
+//		When("inbox_id", FilterOperatorEqualTo, 1).
+//		And("browser_language", FilterOperatorEqualTo, "de").
+//		Do(AssignTeamAction(2), AddLabelAction("vip"))
+type AutomationRuleRequest struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	EventName   WebhookEvent          `json:"event_name"`
+	Active      bool                  `json:"active"`
+	Conditions  []AutomationCondition `json:"conditions"`
+	Actions     []AutomationAction    `json:"actions"`
+}
+
+// NewAutomationRule returns an active rule for the event: conversation_created, conversation_updated,
+// conversation_opened or message_created.
+func NewAutomationRule(name string, eventName WebhookEvent) *AutomationRuleRequest {
+	return &AutomationRuleRequest{
+		Name:       name,
+		EventName:  eventName,
+		Active:     true,
+		Conditions: []AutomationCondition{},
+		Actions:    []AutomationAction{},
+	}
+}
+
+func (automationRuleRequest *AutomationRuleRequest) WithDescription(description string) *AutomationRuleRequest {
+	automationRuleRequest.Description = description
+	return automationRuleRequest
+}
+
+func (automationRuleRequest *AutomationRuleRequest) Inactive() *AutomationRuleRequest {
+	automationRuleRequest.Active = false
+	return automationRuleRequest
+}
+
+// When replaces the conditions of the rule with the given condition, further conditions are added with And and Or.
+func (automationRuleRequest *AutomationRuleRequest) When(attributeKey string, filterOperator string, values ...interface{}) *AutomationRuleRequest {
+	automationRuleRequest.Conditions = []AutomationCondition{}
+	return automationRuleRequest.addCondition(QueryOperatorAnd, attributeKey, filterOperator, values)
+}
+
+// And adds a condition that has to match as well as the previous one.
+func (automationRuleRequest *AutomationRuleRequest) And(attributeKey string, filterOperator string, values ...interface{}) *AutomationRuleRequest {
+	return automationRuleRequest.addCondition(QueryOperatorAnd, attributeKey, filterOperator, values)
+}
+
+// Or adds a condition that has to match if the previous one does not.
+func (automationRuleRequest *AutomationRuleRequest) Or(attributeKey string, filterOperator string, values ...interface{}) *AutomationRuleRequest {
+	return automationRuleRequest.addCondition(QueryOperatorOr, attributeKey, filterOperator, values)
+}
+
+func (automationRuleRequest *AutomationRuleRequest) addCondition(queryOperator string, attributeKey string, filterOperator string, values []interface{}) *AutomationRuleRequest {
+
+	// Chatwoot stores the operator joining two conditions on the first of them
+	if count := len(automationRuleRequest.Conditions); count > 0 {
+		automationRuleRequest.Conditions[count-1].QueryOperator = queryOperator
+	}
+
+	if values == nil {
+		values = []interface{}{}
+	}
+
+	automationRuleRequest.Conditions = append(automationRuleRequest.Conditions, AutomationCondition{
+		AttributeKey:   attributeKey,
+		FilterOperator: filterOperator,
+		Values:         values,
+	})
+
+	return automationRuleRequest
+}
+
+// Do adds actions that are performed in the given order.
+func (automationRuleRequest *AutomationRuleRequest) Do(actions ...AutomationAction) *AutomationRuleRequest {
+	automationRuleRequest.Actions = append(automationRuleRequest.Actions, actions...)
+	return automationRuleRequest
+}
+
+type ListAutomationRulesResponse struct {
+	Payload []AutomationRule `json:"payload"`
+}
+
+type AutomationRuleResponse struct {
+	Payload AutomationRule `json:"payload"`
+}
+
+func (client *ChatwootClient) ListAutomationRules(accountId int64, agentToken string) ([]AutomationRule, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/automation_rules", client.BaseUrl, accountId)
+
+	var listAutomationRulesResponse ListAutomationRulesResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &listAutomationRulesResponse); err != nil {
+		return nil, err
+	}
+
+	return listAutomationRulesResponse.Payload, nil
+}
+
+func (client *ChatwootClient) CreateAutomationRule(accountId int64, agentToken string, automationRuleRequest AutomationRuleRequest) (AutomationRule, error) {
+
+	if agentToken == "" {
+		return AutomationRule{}, errors.New("agentToken is empty. Creating automation rules requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/automation_rules", client.BaseUrl, accountId)
+
+	return client.doAutomationRuleRequest(http.MethodPost, requestURL, agentToken, automationRuleRequest)
+}
+
+// UpdateAutomationRule replaces the rule, conditions and actions that are not part of the request are removed.
+func (client *ChatwootClient) UpdateAutomationRule(accountId int64, automationRuleId int64, agentToken string, automationRuleRequest AutomationRuleRequest) (AutomationRule, error) {
+
+	if agentToken == "" {
+		return AutomationRule{}, errors.New("agentToken is empty. Updating automation rules requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/automation_rules/%v", client.BaseUrl, accountId, automationRuleId)
+
+	return client.doAutomationRuleRequest(http.MethodPatch, requestURL, agentToken, automationRuleRequest)
+}
+
+func (client *ChatwootClient) DeleteAutomationRule(accountId int64, automationRuleId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting automation rules requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/automation_rules/%v", client.BaseUrl, accountId, automationRuleId)
+
+	return client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil)
+}
+
+// CloneAutomationRule creates a copy of the rule and returns the copy.
+func (client *ChatwootClient) CloneAutomationRule(accountId int64, automationRuleId int64, agentToken string) (AutomationRule, error) {
+
+	if agentToken == "" {
+		return AutomationRule{}, errors.New("agentToken is empty. Cloning automation rules requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/automation_rules/%v/clone", client.BaseUrl, accountId, automationRuleId)
+
+	return client.doAutomationRuleRequest(http.MethodPost, requestURL, agentToken, nil)
+}
+
+func (client *ChatwootClient) doAutomationRuleRequest(method string, requestURL string, agentToken string, requestBody interface{}) (AutomationRule, error) {
+
+	var automationRuleResponse AutomationRuleResponse
+
+	if err := client.doJSONRequest(method, requestURL, agentToken, requestBody, &automationRuleResponse); err != nil {
+		return AutomationRule{}, err
+	}
+
+	return automationRuleResponse.Payload, nil
+}
+
+type AutomationRuleUpdate struct {
+	ID   int
+	Rule AutomationRuleRequest
+}
+
+// AutomationRuleDiff lists the changes required to turn the live rules into the desired rules.
+type AutomationRuleDiff struct {
+	Create []AutomationRuleRequest
+	Update []AutomationRuleUpdate
+	Delete []AutomationRule
+}
+
+func (diff AutomationRuleDiff) Empty() bool {
+	return len(diff.Create) == 0 && len(diff.Update) == 0 && len(diff.Delete) == 0
+}
+
+// DiffAutomationRules compares the desired rules with the live rules of the account, rules are matched by name.
+// Live rules without desired rule are deleted, live rules with the same name as an earlier live rule as well.
+func DiffAutomationRules(desired []AutomationRuleRequest, live []AutomationRule) AutomationRuleDiff {
+
+	var diff AutomationRuleDiff
+
+	liveByName := map[string]AutomationRule{}
+
+	for _, automationRule := range live {
+		if _, ok := liveByName[automationRule.Name]; ok {
+			diff.Delete = append(diff.Delete, automationRule)
+			continue
+		}
+		liveByName[automationRule.Name] = automationRule
+	}
+
+	desiredNames := map[string]bool{}
+
+	for _, automationRuleRequest := range desired {
+
+		desiredNames[automationRuleRequest.Name] = true
+
+		automationRule, ok := liveByName[automationRuleRequest.Name]
+
+		if !ok {
+			diff.Create = append(diff.Create, automationRuleRequest)
+			continue
+		}
+
+		if !reflect.DeepEqual(normalizeAutomationRule(automationRule.Request()), normalizeAutomationRule(automationRuleRequest)) {
+			diff.Update = append(diff.Update, AutomationRuleUpdate{ID: automationRule.ID, Rule: automationRuleRequest})
+		}
+	}
+
+	for _, automationRule := range live {
+		if !desiredNames[automationRule.Name] && liveByName[automationRule.Name].ID == automationRule.ID {
+			diff.Delete = append(diff.Delete, automationRule)
+		}
+	}
+
+	return diff
+}
+
+// ApplyAutomationRuleDiff performs the changes of the diff, it stops at the first failing request. Rules are created
+// and updated before obsolete rules are deleted, so that a failure leaves the old rules in place rather than none.
+func (client *ChatwootClient) ApplyAutomationRuleDiff(accountId int64, agentToken string, diff AutomationRuleDiff) error {
+
+	for _, automationRuleRequest := range diff.Create {
+		if _, err := client.CreateAutomationRule(accountId, agentToken, automationRuleRequest); err != nil {
+			return err
+		}
+	}
+
+	for _, update := range diff.Update {
+		if _, err := client.UpdateAutomationRule(accountId, int64(update.ID), agentToken, update.Rule); err != nil {
+			return err
+		}
+	}
+
+	for _, automationRule := range diff.Delete {
+		if err := client.DeleteAutomationRule(accountId, int64(automationRule.ID), agentToken); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unorderedParams are the actions whose parameters are a set, Chatwoot does not preserve their order.
+var unorderedParams = map[string]bool{
+	ActionAddLabel:    true,
+	ActionRemoveLabel: true,
+}
+
+// normalizeAutomationRule returns the rule in a form that is equal for a desired rule and the rule Chatwoot returns
+// for it. The query operator of the last condition is dropped, as it joins nothing, and values and parameters are
+// compared by their text, as Chatwoot may return numbers as strings. Empty parameters equal missing ones and the
+// order of sets like labels and team IDs is ignored.
+func normalizeAutomationRule(automationRuleRequest AutomationRuleRequest) interface{} {
+
+	conditions := make([]interface{}, len(automationRuleRequest.Conditions))
+
+	for i, condition := range automationRuleRequest.Conditions {
+		queryOperator := condition.QueryOperator
+		if i == len(automationRuleRequest.Conditions)-1 {
+			queryOperator = ""
+		}
+		conditions[i] = []interface{}{condition.AttributeKey, condition.FilterOperator, normalizeValue(condition.Values), queryOperator}
+	}
+
+	actions := make([]interface{}, len(automationRuleRequest.Actions))
+
+	for i, action := range automationRuleRequest.Actions {
+		params := normalizeValue(action.ActionParams)
+		if unorderedParams[action.ActionName] {
+			sortValues(params.([]interface{}))
+		}
+		actions[i] = []interface{}{action.ActionName, params}
+	}
+
+	return []interface{}{
+		automationRuleRequest.Name,
+		automationRuleRequest.Description,
+		automationRuleRequest.EventName,
+		automationRuleRequest.Active,
+		conditions,
+		actions,
+	}
+}
+
+// normalizeValue returns the value with its numbers and booleans as strings, nil is returned as empty list.
+func normalizeValue(value interface{}) interface{} {
+
+	var decoded interface{}
+
+	if encoded, err := json.Marshal(value); err != nil || json.Unmarshal(encoded, &decoded) != nil {
+		return fmt.Sprint(value)
+	}
+
+	if decoded == nil {
+		return []interface{}{}
+	}
+
+	return normalizeDecodedValue(decoded)
+}
+
+func normalizeDecodedValue(value interface{}) interface{} {
+
+	switch value := value.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case []interface{}:
+		values := make([]interface{}, len(value))
+		for i, element := range value {
+			values[i] = normalizeDecodedValue(element)
+		}
+		return values
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(value))
+		for key, element := range value {
+			values[key] = normalizeDecodedValue(element)
+		}
+		if teamIds, ok := values["team_ids"].([]interface{}); ok {
+			sortValues(teamIds)
+		}
+		return values
+	}
+
+	return value
+}
+
+func sortValues(values []interface{}) {
+	sort.Slice(values, func(i int, j int) bool {
+		return fmt.Sprint(values[i]) < fmt.Sprint(values[j])
+	})
+}
@@ -0,0 +1,157 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAutomationRuleBuilder(t *testing.T) {
+
+	automationRuleRequest := NewAutomationRule("VIP routing", EventConversationCreated).
+		When("inbox_id", FilterOperatorEqualTo, 1).
+		Or("browser_language", FilterOperatorEqualTo, "de").
+		And("email", FilterOperatorContains, "@example.com").
+		Do(AssignTeamAction(2), AddLabelAction("vip", "de"), ResolveConversationAction())
+
+	encoded, _ := json.Marshal(automationRuleRequest)
+
+	expected := `{"name":"VIP routing","event_name":"conversation_created","active":true,"conditions":[` +
+		`{"attribute_key":"inbox_id","filter_operator":"equal_to","values":[1],"query_operator":"or"},` +
+		`{"attribute_key":"browser_language","filter_operator":"equal_to","values":["de"],"query_operator":"and"},` +
+		`{"attribute_key":"email","filter_operator":"contains","values":["@example.com"]}],"actions":[` +
+		`{"action_name":"assign_team","action_params":[2]},{"action_name":"add_label","action_params":["vip","de"]},` +
+		`{"action_name":"resolve_conversation"}]}`
+
+	if string(encoded) != expected {
+		t.Fatalf("unexpected request:\n%s\n%s", encoded, expected)
+	}
+
+	automationRuleRequest.When("content", FilterOperatorContains, "refund")
+
+	if len(automationRuleRequest.Conditions) != 1 || automationRuleRequest.Conditions[0].AttributeKey != "content" ||
+		automationRuleRequest.Conditions[0].QueryOperator != "" {
+		t.Fatalf("expected When to replace the conditions, got %+v", automationRuleRequest.Conditions)
+	}
+
+}
+
+func TestAutomationRules(t *testing.T) {
+
+	var calls []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/accounts/1/automation_rules":
+			w.Write([]byte(`{"payload": [
+				{"id": 1, "name": "VIP routing", "event_name": "conversation_created", "active": true,
+					"conditions": [{"attribute_key": "inbox_id", "filter_operator": "equal_to", "values": [1], "query_operator": null}],
+					"actions": [{"action_name": "assign_team", "action_params": [2]}], "created_on": 1709287200},
+				{"id": 2, "name": "Spam", "event_name": "message_created", "active": true,
+					"conditions": [{"attribute_key": "content", "filter_operator": "contains", "values": ["casino"]}],
+					"actions": [{"action_name": "resolve_conversation", "action_params": []}]},
+				{"id": 3, "name": "Legacy", "event_name": "conversation_updated", "active": false, "conditions": [], "actions": []}
+			]}`))
+		case "POST /api/v1/accounts/1/automation_rules/2/clone":
+			w.Write([]byte(`{"payload": {"id": 4, "name": "Spam", "event_name": "message_created"}}`))
+		case "POST /api/v1/accounts/1/automation_rules", "PATCH /api/v1/accounts/1/automation_rules/2":
+			w.Write([]byte(`{"payload": {"id": 5}}`))
+		case "DELETE /api/v1/accounts/1/automation_rules/3":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	live, err := client.ListAutomationRules(1, "agent-token")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(live) != 3 || live[0].CreatedOn.Unix() != 1709287200 || live[1].Actions[0].ActionName != ActionResolveConversation {
+		t.Fatalf("unexpected rules: %+v", live)
+	}
+
+	if clone, err := client.CloneAutomationRule(1, 2, "agent-token"); err != nil || clone.ID != 4 {
+		t.Fatalf("unexpected clone %+v: %v", clone, err)
+	}
+
+	desired := []AutomationRuleRequest{
+		*NewAutomationRule("VIP routing", EventConversationCreated).When("inbox_id", FilterOperatorEqualTo, 1).Do(AssignTeamAction(2)),
+		*NewAutomationRule("Spam", EventMessageCreated).When("content", FilterOperatorContains, "casino", "poker").Do(ResolveConversationAction()),
+		*NewAutomationRule("Welcome", EventConversationCreated).Do(SendMessageAction("Hi!")),
+	}
+
+	diff := DiffAutomationRules(desired, live)
+
+	if len(diff.Create) != 1 || diff.Create[0].Name != "Welcome" || len(diff.Update) != 1 || diff.Update[0].ID != 2 ||
+		len(diff.Delete) != 1 || diff.Delete[0].ID != 3 {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+
+	calls = nil
+
+	if err := client.ApplyAutomationRuleDiff(1, "agent-token", diff); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 3 || calls[0] != "POST /api/v1/accounts/1/automation_rules" || calls[1] != "PATCH /api/v1/accounts/1/automation_rules/2" ||
+		calls[2] != "DELETE /api/v1/accounts/1/automation_rules/3" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+
+	if !DiffAutomationRules(desired[:1], live[:1]).Empty() {
+		t.Fatal("expected no changes for equal rules")
+	}
+
+}
+
+func TestDiffAutomationRulesNormalizesLiveRules(t *testing.T) {
+
+	desired := []AutomationRuleRequest{
+		*NewAutomationRule("VIP routing", EventConversationCreated).
+			When("inbox_id", FilterOperatorEqualTo, 1).
+			Or("browser_language", FilterOperatorEqualTo, "de").
+			Do(AssignTeamAction(2), AddLabelAction("vip", "de"), SendEmailToTeamAction("New VIP", 3, 4), ResolveConversationAction()),
+	}
+
+	// the rule as returned by Chatwoot after creating it
+	var live []AutomationRule
+
+	err := json.Unmarshal([]byte(`[{"id": 1, "name": "VIP routing", "description": null, "event_name": "conversation_created", "active": true,
+		"conditions": [
+			{"attribute_key": "inbox_id", "filter_operator": "equal_to", "values": ["1"], "query_operator": "or"},
+			{"attribute_key": "browser_language", "filter_operator": "equal_to", "values": ["de"], "query_operator": "and"}],
+		"actions": [
+			{"action_name": "assign_team", "action_params": ["2"]},
+			{"action_name": "add_label", "action_params": ["de", "vip"]},
+			{"action_name": "send_email_to_team", "action_params": [{"message": "New VIP", "team_ids": ["4", 3]}]},
+			{"action_name": "resolve_conversation", "action_params": []}]}]`), &live)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := DiffAutomationRules(desired, live); !diff.Empty() {
+		t.Fatalf("expected no changes for the rule returned by Chatwoot, got %+v", diff)
+	}
+
+	desired[0].Actions[0] = AssignTeamAction(5)
+
+	if diff := DiffAutomationRules(desired, live); len(diff.Update) != 1 {
+		t.Fatalf("expected an update for a changed team, got %+v", diff)
+	}
+
+}
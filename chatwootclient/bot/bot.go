@@ -0,0 +1,168 @@
+// Package bot provides a runtime for Chatwoot agent bots. It consumes the agent bot callbacks, routes incoming
+// messages to handler funcs and lets them answer through the ChatwootClient.
+package bot
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient/webhook"
+)
+
+// HandlerFunc handles an incoming message, errors make the callback respond with 500.
+type HandlerFunc func(ctx *Context) error
+
+// Matcher decides whether a route handles a message. The returned strings are passed to the handler as Context.Match,
+// e.g. the submatches of a regular expression.
+type Matcher func(event *webhook.MessageEvent) ([]string, bool)
+
+type route struct {
+	matcher     Matcher
+	handlerFunc HandlerFunc
+}
+
+// Bot receives the callbacks of a Chatwoot agent bot. Only incoming public messages are routed, the outgoing messages
+// of the bot itself, messages of agents and private notes are ignored. Routes are matched in the order they were
+// added, the first matching route handles the message.
+//
+// AgentBotToken is used to reply and to change the conversation status. AgentToken is optional and only required
+// for operations agent bots are not allowed to perform, like adding labels.
+type Bot struct {
+	Client        *chatwootclient.ChatwootClient
+	AgentBotToken string
+	AgentToken    string
+
+	mutex           sync.RWMutex
+	routes          []route
+	fallbackHandler HandlerFunc
+	webhookHandler  *webhook.Handler
+}
+
+func New(client *chatwootclient.ChatwootClient, agentBotToken string) *Bot {
+
+	bot := &Bot{
+		Client:         client,
+		AgentBotToken:  agentBotToken,
+		webhookHandler: webhook.NewHandler(),
+	}
+
+	bot.webhookHandler.OnMessageCreated(bot.handleMessage)
+
+	return bot
+}
+
+// Handle adds a route for the messages accepted by the matcher.
+func (bot *Bot) Handle(matcher Matcher, handlerFunc HandlerFunc) {
+
+	bot.mutex.Lock()
+	defer bot.mutex.Unlock()
+
+	bot.routes = append(bot.routes, route{matcher, handlerFunc})
+}
+
+// HandleInbox adds a route for messages of the given inboxes.
+func (bot *Bot) HandleInbox(handlerFunc HandlerFunc, inboxIds ...int) {
+	bot.Handle(MatchInbox(inboxIds...), handlerFunc)
+}
+
+// HandleLabel adds a route for messages of conversations with the given label.
+func (bot *Bot) HandleLabel(label string, handlerFunc HandlerFunc) {
+	bot.Handle(MatchLabel(label), handlerFunc)
+}
+
+// HandleRegexp adds a route for messages whose content matches the regular expression.
+func (bot *Bot) HandleRegexp(pattern *regexp.Regexp, handlerFunc HandlerFunc) {
+	bot.Handle(MatchRegexp(pattern), handlerFunc)
+}
+
+// HandleDefault sets the handler for messages no route matches.
+func (bot *Bot) HandleDefault(handlerFunc HandlerFunc) {
+
+	bot.mutex.Lock()
+	defer bot.mutex.Unlock()
+
+	bot.fallbackHandler = handlerFunc
+}
+
+// Webhook returns the webhook handler of the bot, e.g. to register handlers for other events.
+func (bot *Bot) Webhook() *webhook.Handler {
+	return bot.webhookHandler
+}
+
+// ServeHTTP handles the agent bot callbacks, configure its URL as outgoing url of the agent bot.
+func (bot *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bot.webhookHandler.ServeHTTP(w, r)
+}
+
+func (bot *Bot) handleMessage(event *webhook.MessageEvent) error {
+
+	if event.MessageType != chatwootclient.MessageTypeIncoming || event.Private {
+		return nil
+	}
+
+	bot.mutex.RLock()
+	routes := bot.routes
+	fallbackHandler := bot.fallbackHandler
+	bot.mutex.RUnlock()
+
+	for _, route := range routes {
+		if match, ok := route.matcher(event); ok {
+			return route.handlerFunc(bot.newContext(event, match))
+		}
+	}
+
+	if fallbackHandler != nil {
+		return fallbackHandler(bot.newContext(event, nil))
+	}
+
+	return nil
+}
+
+func MatchInbox(inboxIds ...int) Matcher {
+	return func(event *webhook.MessageEvent) ([]string, bool) {
+		for _, inboxId := range inboxIds {
+			if event.Inbox.ID == inboxId || event.InboxID == inboxId || event.Conversation.InboxID == inboxId {
+				return nil, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// MatchLabel matches messages of conversations with the label, labels are compared case insensitive.
+func MatchLabel(label string) Matcher {
+	return func(event *webhook.MessageEvent) ([]string, bool) {
+		for _, conversationLabel := range event.Conversation.Labels {
+			if strings.EqualFold(conversationLabel, label) {
+				return nil, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// MatchRegexp matches messages whose content matches the pattern, the submatches are passed as Context.Match.
+func MatchRegexp(pattern *regexp.Regexp) Matcher {
+	return func(event *webhook.MessageEvent) ([]string, bool) {
+		match := pattern.FindStringSubmatch(event.Content)
+		return match, match != nil
+	}
+}
+
+// MatchAll matches messages that are matched by all matchers, the matches of the matchers are concatenated.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(event *webhook.MessageEvent) ([]string, bool) {
+		var matches []string
+		for _, matcher := range matchers {
+			match, ok := matcher(event)
+			if !ok {
+				return nil, false
+			}
+			matches = append(matches, match...)
+		}
+		return matches, true
+	}
+}
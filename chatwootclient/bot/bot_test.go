@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+type chatwootMock struct {
+	mutex    sync.Mutex
+	requests []string
+}
+
+func (mock *chatwootMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/messages"):
+		mock.requests = append(mock.requests, "message: "+body["content"].(string))
+	case strings.HasSuffix(r.URL.Path, "/toggle_status"):
+		mock.requests = append(mock.requests, "status: "+body["status"].(string))
+	case strings.HasSuffix(r.URL.Path, "/execute"):
+		conversationIds, _ := json.Marshal(body["conversation_ids"])
+		mock.requests = append(mock.requests, "macro: "+r.URL.Path+" "+string(conversationIds))
+	case strings.HasSuffix(r.URL.Path, "/labels") && r.Method == http.MethodGet:
+		// a label was added after the event was sent
+		w.Write([]byte(`{"payload": ["new", "billing"]}`))
+		return
+	case strings.HasSuffix(r.URL.Path, "/labels"):
+		labels, _ := json.Marshal(body["labels"])
+		mock.requests = append(mock.requests, "labels: "+string(labels)+" "+r.Header.Get("api_access_token"))
+	}
+
+	w.Write([]byte(`{}`))
+}
+
+func messagePayload(messageType string, private bool, inboxId int, labels string, content string) string {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"event":        "message_created",
+		"id":           1,
+		"content":      content,
+		"message_type": messageType,
+		"private":      private,
+		"account":      map[string]interface{}{"id": 1},
+		"inbox":        map[string]interface{}{"id": inboxId},
+		"conversation": json.RawMessage(fmt.Sprintf(`{"id": 9, "inbox_id": %d, "labels": %s}`, inboxId, labels)),
+	})
+	return string(payload)
+}
+
+func TestBot(t *testing.T) {
+
+	mock := &chatwootMock{}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client := chatwootclient.NewChatwootClient(server.URL)
+
+	bot := New(&client, "bot-token")
+	bot.AgentToken = "agent-token"
+
+	bot.HandleLabel("vip", func(ctx *Context) error {
+		return ctx.Handoff()
+	})
+
+	bot.HandleRegexp(regexp.MustCompile(`(?i)order #(\d+)`), func(ctx *Context) error {
+		if err := ctx.AddLabel("order"); err != nil {
+			return err
+		}
+		return ctx.Reply("Looking up order " + ctx.Match[1])
+	})
+
+	bot.HandleInbox(func(ctx *Context) error {
+		if err := ctx.ExecuteMacro(3); err != nil {
+			return err
+		}
+		return ctx.ReplyPrivate("message from inbox 2")
+	}, 2)
+
+	bot.HandleDefault(func(ctx *Context) error {
+		return ctx.Resolve()
+	})
+
+	payloads := []string{
+		messagePayload("incoming", false, 1, `["vip"]`, "Hello"),
+		messagePayload("incoming", false, 1, `["new"]`, "Where is order #1001?"),
+		messagePayload("incoming", false, 2, `[]`, "Hi"),
+		messagePayload("incoming", false, 1, `[]`, "Bye"),
+		// ignored: own outgoing message and private note
+		messagePayload("outgoing", false, 1, `[]`, "Looking up order #1001"),
+		messagePayload("incoming", true, 1, `[]`, "order #1001"),
+	}
+
+	for _, payload := range payloads {
+		recorder := httptest.NewRecorder()
+		bot.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/bot", strings.NewReader(payload)))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d for %s", recorder.Code, payload)
+		}
+	}
+
+	expected := []string{
+		"status: open",
+		`labels: ["new","billing","order"] agent-token`,
+		"message: Looking up order 1001",
+		"macro: /api/v1/accounts/1/macros/3/execute [9]",
+		"message: message from inbox 2",
+		"status: resolved",
+	}
+
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+
+	if strings.Join(mock.requests, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected requests:\n%s", strings.Join(mock.requests, "\n"))
+	}
+
+}
@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient/webhook"
+)
+
+// Context is passed to the handler funcs, it is bound to the conversation of the incoming message.
+type Context struct {
+	Event *webhook.MessageEvent
+	Match []string
+
+	bot *Bot
+}
+
+func (bot *Bot) newContext(event *webhook.MessageEvent, match []string) *Context {
+	return &Context{
+		Event: event,
+		Match: match,
+		bot:   bot,
+	}
+}
+
+func (ctx *Context) AccountID() int64 {
+	return int64(ctx.Event.Account.ID)
+}
+
+func (ctx *Context) ConversationID() int64 {
+	if ctx.Event.Conversation.ID != 0 {
+		return int64(ctx.Event.Conversation.ID)
+	}
+	return int64(ctx.Event.ConversationID)
+}
+
+// Content returns the content of the incoming message.
+func (ctx *Context) Content() string {
+	return ctx.Event.Content
+}
+
+// Client returns the client of the bot.
+func (ctx *Context) Client() *chatwootclient.ChatwootClient {
+	return ctx.bot.Client
+}
+
+// Reply sends an outgoing message to the conversation.
+func (ctx *Context) Reply(content string) error {
+
+	_, err := ctx.bot.Client.CreateOutgoingMessage(ctx.AccountID(), ctx.ConversationID(), ctx.bot.AgentBotToken, content)
+
+	return err
+}
+
+// ReplyPrivate adds a private note to the conversation that is only visible to agents.
+func (ctx *Context) ReplyPrivate(content string) error {
+
+	_, err := ctx.bot.Client.CreateOutgoingPrivateMessage(ctx.AccountID(), ctx.ConversationID(), ctx.bot.AgentBotToken, content)
+
+	return err
+}
+
+// Handoff hands the conversation over to the agents by opening it.
+func (ctx *Context) Handoff() error {
+
+	return ctx.bot.Client.ToggleConversationStatus(ctx.AccountID(), ctx.ConversationID(), ctx.bot.AgentBotToken, chatwootclient.ConversationStatusOpen)
+}
+
+// Resolve resolves the conversation.
+func (ctx *Context) Resolve() error {
+
+	return ctx.bot.Client.ToggleConversationStatus(ctx.AccountID(), ctx.ConversationID(), ctx.bot.AgentBotToken, chatwootclient.ConversationStatusResolved)
+}
+
+// AddLabel adds the label to the labels of the conversation, this requires the AgentToken of the bot. The current
+// labels are fetched first, as the labels of the event might be outdated.
+func (ctx *Context) AddLabel(label string) error {
+
+	labels, err := ctx.bot.Client.ListConversationLabels(ctx.AccountID(), ctx.ConversationID(), ctx.bot.AgentToken)
+
+	if err != nil {
+		return err
+	}
+
+	ctx.Event.Conversation.Labels = labels
+
+	for _, existing := range labels {
+		if strings.EqualFold(existing, label) {
+			return nil
+		}
+	}
+
+	// Chatwoot replaces the labels of the conversation with the given labels
+	labels = append(append([]string(nil), labels...), label)
+
+	if err := ctx.bot.Client.AddLabels(ctx.AccountID(), ctx.ConversationID(), ctx.bot.AgentToken, labels); err != nil {
+		return err
+	}
+
+	ctx.Event.Conversation.Labels = labels
+
+	return nil
+}
+
+// ExecuteMacro applies the macro to the conversation, this requires the AgentToken of the bot.
+func (ctx *Context) ExecuteMacro(macroId int64) error {
+
+	return ctx.bot.Client.ExecuteMacro(ctx.AccountID(), macroId, ctx.bot.AgentToken, []int64{ctx.ConversationID()})
+}
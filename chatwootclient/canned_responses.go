@@ -0,0 +1,227 @@
+package chatwootclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type CannedResponse struct {
+	ID        int    `json:"id"`
+	AccountID int    `json:"account_id,omitempty"`
+	ShortCode string `json:"short_code"`
+	Content   string `json:"content"`
+}
+
+type CannedResponseRequest struct {
+	ShortCode string `json:"short_code,omitempty"`
+	Content   string `json:"content,omitempty"`
+}
+
+// ListCannedResponses returns the canned responses of the account. If search is not empty, only canned responses
+// whose short code or content contain the search term are returned.
+func (client *ChatwootClient) ListCannedResponses(accountId int64, agentToken string, search string) ([]CannedResponse, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/canned_responses", client.BaseUrl, accountId)
+
+	if search != "" {
+		requestURL += "?search=" + url.QueryEscape(search)
+	}
+
+	var cannedResponses []CannedResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &cannedResponses); err != nil {
+		return nil, err
+	}
+
+	return cannedResponses, nil
+}
+
+func (client *ChatwootClient) CreateCannedResponse(accountId int64, agentToken string, cannedResponseRequest CannedResponseRequest) (CannedResponse, error) {
+
+	if agentToken == "" {
+		return CannedResponse{}, errors.New("agentToken is empty. Creating canned responses requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/canned_responses", client.BaseUrl, accountId)
+
+	var cannedResponse CannedResponse
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, cannedResponseRequest, &cannedResponse); err != nil {
+		return CannedResponse{}, err
+	}
+
+	return cannedResponse, nil
+}
+
+func (client *ChatwootClient) UpdateCannedResponse(accountId int64, cannedResponseId int64, agentToken string, cannedResponseRequest CannedResponseRequest) (CannedResponse, error) {
+
+	if agentToken == "" {
+		return CannedResponse{}, errors.New("agentToken is empty. Updating canned responses requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/canned_responses/%v", client.BaseUrl, accountId, cannedResponseId)
+
+	var cannedResponse CannedResponse
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, cannedResponseRequest, &cannedResponse); err != nil {
+		return CannedResponse{}, err
+	}
+
+	return cannedResponse, nil
+}
+
+func (client *ChatwootClient) DeleteCannedResponse(accountId int64, cannedResponseId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting canned responses requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/canned_responses/%v", client.BaseUrl, accountId, cannedResponseId)
+
+	return client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil)
+}
+
+// RenderContext provides the values of the variables of canned responses. Contact and Agent default to the sender
+// and the assignee of the conversation.
+type RenderContext struct {
+	Contact      *Contact
+	Conversation *Conversation
+	Agent        *Agent
+}
+
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_.]+)\s*(?:\|\s*default\s*:\s*(?:'([^']*)'|"([^"]*)")\s*)?\}\}`)
+
+// RenderCannedResponse substitutes the Chatwoot variables in the content, e.g. {{contact.name}}, {{agent.first_name}},
+// {{conversation.id}} or {{contact.custom_attribute.order_id}}. Like in Chatwoot, variables without value are
+// replaced with an empty string unless a default is given using {{contact.name | default: 'there'}}.
+func RenderCannedResponse(content string, renderContext RenderContext) string {
+
+	return templateVariablePattern.ReplaceAllStringFunc(content, func(variable string) string {
+
+		match := templateVariablePattern.FindStringSubmatch(variable)
+
+		if value := renderContext.value(match[1]); value != "" {
+			return value
+		}
+
+		return match[2] + match[3]
+	})
+}
+
+func (renderContext RenderContext) value(variable string) string {
+
+	contact := renderContext.Contact
+	agent := renderContext.Agent
+
+	if conversation := renderContext.Conversation; conversation != nil {
+		if contact == nil {
+			contact = conversation.Meta.Sender
+		}
+		if agent == nil {
+			agent = conversation.Meta.Assignee
+		}
+	}
+
+	object, field, _ := strings.Cut(variable, ".")
+
+	switch object {
+	case "contact":
+		if contact == nil {
+			return ""
+		}
+		switch field {
+		case "id":
+			return fmt.Sprint(contact.ID)
+		case "name":
+			return contact.Name
+		case "first_name":
+			return firstName(contact.Name)
+		case "last_name":
+			return lastName(contact.Name)
+		case "email":
+			return contact.Email
+		case "phone":
+			return contact.PhoneNumber
+		}
+		return customAttribute(contact.CustomAttributes, field)
+
+	case "agent":
+		if agent == nil {
+			return ""
+		}
+		switch field {
+		case "id":
+			return fmt.Sprint(agent.ID)
+		case "name":
+			return agentName(agent)
+		case "first_name":
+			return firstName(agentName(agent))
+		case "last_name":
+			return lastName(agentName(agent))
+		case "email":
+			return agent.Email
+		}
+
+	case "conversation":
+		if renderContext.Conversation == nil {
+			return ""
+		}
+		if field == "id" {
+			return fmt.Sprint(renderContext.Conversation.ID)
+		}
+		return customAttribute(renderContext.Conversation.CustomAttributes, field)
+	}
+
+	return ""
+}
+
+// customAttribute returns the value of a custom_attribute.{key} variable.
+func customAttribute(customAttributes map[string]interface{}, field string) string {
+
+	if !strings.HasPrefix(field, "custom_attribute.") {
+		return ""
+	}
+
+	value, ok := customAttributes[strings.TrimPrefix(field, "custom_attribute.")]
+
+	if !ok || value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// agentName returns the name the agent is shown with to contacts, first and last name are derived from it like from
+// the name of contacts.
+func agentName(agent *Agent) string {
+	if agent.AvailableName != "" {
+		return agent.AvailableName
+	}
+	return agent.Name
+}
+
+func firstName(name string) string {
+	names := strings.Fields(name)
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+func lastName(name string) string {
+	names := strings.Fields(name)
+	if len(names) < 2 {
+		return ""
+	}
+	return names[len(names)-1]
+}
+
+// SendCannedResponse renders the canned response for the conversation and sends it as outgoing message.
+func (client *ChatwootClient) SendCannedResponse(accountId int64, conversationId int64, agentBotToken string, cannedResponse CannedResponse, renderContext RenderContext) (CreateNewMessageResponse, error) {
+
+	return client.CreateOutgoingMessage(accountId, conversationId, agentBotToken, RenderCannedResponse(cannedResponse.Content, renderContext))
+}
@@ -0,0 +1,99 @@
+package chatwootclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenderCannedResponse(t *testing.T) {
+
+	conversation := &Conversation{
+		ID: 42,
+		CustomAttributes: map[string]interface{}{
+			"order_id": "#1001",
+		},
+		Meta: ConversationMeta{
+			Sender: &Contact{
+				Name: "Jane Doe",
+				CustomAttributes: map[string]interface{}{
+					"tier": "gold",
+				},
+			},
+			Assignee: &Agent{
+				Name:          "John Smith",
+				AvailableName: "John",
+			},
+		},
+	}
+
+	rendered := RenderCannedResponse("Hi {{contact.first_name}}, {{ agent.name }} here about order {{conversation.custom_attribute.order_id}} "+
+		"of conversation {{conversation.id}} ({{contact.custom_attribute.tier}}, {{contact.email | default: 'no email'}}{{contact.phone}}).",
+		RenderContext{Conversation: conversation})
+
+	expected := "Hi Jane, John here about order #1001 of conversation 42 (gold, no email)."
+
+	if rendered != expected {
+		t.Fatalf("expected %q, got %q", expected, rendered)
+	}
+
+}
+
+func TestRenderCannedResponseNames(t *testing.T) {
+
+	renderContext := RenderContext{
+		Contact: &Contact{Name: "Jane Mary Doe"},
+		Agent:   &Agent{Name: "John Smith", AvailableName: "Johnny B"},
+	}
+
+	rendered := RenderCannedResponse("{{contact.name}}: {{contact.first_name}} {{contact.last_name}}, "+
+		"{{agent.name}}: {{agent.first_name}} {{agent.last_name}}", renderContext)
+
+	expected := "Jane Mary Doe: Jane Doe, Johnny B: Johnny B"
+
+	if rendered != expected {
+		t.Fatalf("expected %q, got %q", expected, rendered)
+	}
+
+	renderContext.Agent = &Agent{Name: "John Smith"}
+
+	if rendered := RenderCannedResponse("{{agent.first_name}} {{agent.last_name}}", renderContext); rendered != "John Smith" {
+		t.Fatalf("expected the name of the agent without available name, got %q", rendered)
+	}
+
+}
+
+func TestListCannedResponses(t *testing.T) {
+
+	var search string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path != "/api/v1/accounts/1/canned_responses" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		search = r.URL.Query().Get("search")
+
+		w.Write([]byte(`[{"id": 1, "short_code": "shipping", "content": "Hi {{contact.name}}, shipping takes 3 days."}]`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	cannedResponses, err := client.ListCannedResponses(1, "agent-token", "shipping time")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if search != "shipping time" || len(cannedResponses) != 1 || cannedResponses[0].ShortCode != "shipping" {
+		t.Fatalf("unexpected canned responses for %q: %+v", search, cannedResponses)
+	}
+
+}
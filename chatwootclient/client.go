@@ -6,30 +6,33 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"mime"
-	"mime/multipart"
 	"net/http"
-	"net/textproto"
-	"net/url"
-	"path"
-	"path/filepath"
 )
 
 // Please note that certain functions like to add labels or assign agents are blocked when using an Agent Bot Token
 // therefore an AgentToken has to be provided. The client uses the AgentBotToken wherever possible.
 type ChatwootClient struct {
 	BaseUrl string
+
+	// LabelCatalog enables the strict label mode when set: labels passed to AddLabels, AddLabel and AddContactLabels
+	// are validated against the labels of the account and unknown labels are rejected with ErrUnknownLabel.
+	LabelCatalog *LabelCatalog
+
+	// CustomAttributeCatalog enables the validation of custom attributes when set: custom attributes passed to
+	// CreateContact and UpdateConversationCustomAttributes are validated against the custom attribute definitions
+	// of the account and invalid attributes are rejected with ErrInvalidCustomAttribute.
+	CustomAttributeCatalog *CustomAttributeCatalog
 }
 
 func NewChatwootClient(baseUrl string) ChatwootClient {
 	return ChatwootClient{
-		baseUrl,
+		BaseUrl: baseUrl,
 	}
 }
 
 func NewChatwootClientWithAgentToken(baseUrl string) ChatwootClient {
 	return ChatwootClient{
-		baseUrl,
+		BaseUrl: baseUrl,
 	}
 }
 
@@ -53,8 +56,15 @@ type Payload struct {
 }
 
 type Contact struct {
-	ID             int            `json:"id"`
-	ContactInboxes []ContactInbox `json:"contact_inboxes"`
+	ID                   int                    `json:"id"`
+	Name                 string                 `json:"name,omitempty"`
+	Email                string                 `json:"email,omitempty"`
+	PhoneNumber          string                 `json:"phone_number,omitempty"`
+	Identifier           string                 `json:"identifier,omitempty"`
+	Thumbnail            string                 `json:"thumbnail,omitempty"`
+	CustomAttributes     map[string]interface{} `json:"custom_attributes,omitempty"`
+	AdditionalAttributes map[string]interface{} `json:"additional_attributes,omitempty"`
+	ContactInboxes       []ContactInbox         `json:"contact_inboxes"`
 }
 
 type ContactInbox struct {
@@ -63,6 +73,10 @@ type ContactInbox struct {
 
 func (client *ChatwootClient) CreateContact(accountId int64, agentToken string, createContactRequest CreateContactRequest) (CreateContactResponse, error) {
 
+	if err := client.validateCustomAttributes(accountId, agentToken, AttributeModelContact, createContactRequest.CustomAttributes); err != nil {
+		return CreateContactResponse{}, err
+	}
+
 	url := fmt.Sprintf("%s/api/v1/accounts/%v/contacts", client.BaseUrl, accountId)
 
 	requestJSON, err := json.Marshal(createContactRequest)
@@ -209,9 +223,10 @@ func (client *ChatwootClient) GetMessages(accountId int64, conversationId int64,
 
 // Struct that allows to build minimal create message requests.
 type CreateNewMessageRequest struct {
-	Content     string `json:"content"`
-	MessageType string `json:"message_type"`
-	Private     bool   `json:"private"`
+	Content        string          `json:"content"`
+	MessageType    string          `json:"message_type"`
+	Private        bool            `json:"private"`
+	TemplateParams *TemplateParams `json:"template_params,omitempty"` // only supported by WhatsApp inboxes
 }
 
 type CreateNewMessageResponse struct {
@@ -320,6 +335,10 @@ func (client *ChatwootClient) AddLabels(accountId int64, conversationId int64, a
 		return errors.New("agentToken is empty. Adding labels requires a Chatwoot agent token")
 	}
 
+	if err := client.validateLabels(accountId, agentToken, labels); err != nil {
+		return err
+	}
+
 	url := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/labels", client.BaseUrl, accountId, conversationId)
 
 	requestBody := AddLabelsRequest{
@@ -339,6 +358,10 @@ func (client *ChatwootClient) AddLabels(accountId int64, conversationId int64, a
 
 	response, err := http.DefaultClient.Do(request)
 
+	if err != nil {
+		return err
+	}
+
 	if response.StatusCode != 200 {
 		return errors.New("Request failed" + response.Status)
 	}
@@ -353,6 +376,10 @@ func (client *ChatwootClient) AddLabel(accountId int64, conversationId int64, ag
 		return errors.New("agentToken is empty. Adding labels requires a Chatwoot agent token")
 	}
 
+	if err := client.validateLabels(accountId, agentToken, []string{label}); err != nil {
+		return err
+	}
+
 	url := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/labels", client.BaseUrl, accountId, conversationId)
 
 	requestBody := AddLabelsRequest{
@@ -372,6 +399,10 @@ func (client *ChatwootClient) AddLabel(accountId int64, conversationId int64, ag
 
 	response, err := http.DefaultClient.Do(request)
 
+	if err != nil {
+		return err
+	}
+
 	if response.StatusCode != 200 {
 		return errors.New("Request failed" + response.Status)
 	}
@@ -398,6 +429,10 @@ func (client *ChatwootClient) Assign(accountId int64, conversationId int64, agen
 
 	response, err := http.DefaultClient.Do(request)
 
+	if err != nil {
+		return err
+	}
+
 	if response.StatusCode != 200 {
 		return errors.New("Request failed" + response.Status)
 	}
@@ -425,6 +460,10 @@ func (client *ChatwootClient) AssignTeam(accountId int64, conversationId int64,
 
 	response, err := http.DefaultClient.Do(request)
 
+	if err != nil {
+		return err
+	}
+
 	if response.StatusCode != 200 {
 		return errors.New("Request failed" + response.Status)
 	}
@@ -433,6 +472,8 @@ func (client *ChatwootClient) AssignTeam(accountId int64, conversationId int64,
 
 }
 
+// SendImageMessage downloads the image from the given URL and sends it as attachment of an outgoing message.
+// Use SendAttachmentsMessage to send multiple attachments or attachments from other sources.
 func (client *ChatwootClient) SendImageMessage(
 	accountId int64,
 	conversationId int64,
@@ -441,122 +482,12 @@ func (client *ChatwootClient) SendImageMessage(
 	content string,
 ) (CreateNewMessageResponse, error) {
 
-	apiUrl := fmt.Sprintf("%s/api/v1/accounts/%d/conversations/%d/messages", client.BaseUrl, accountId, conversationId)
-
-	// 创建一个缓冲区来写入 multipart 表单数据
-	var buf bytes.Buffer
-
-	// 使用边界 '----WebKitFormBoundary'，与您的 curl 命令一致
-	boundary := "----WebKitFormBoundary"
-	mw := multipart.NewWriter(&buf)
-	mw.SetBoundary(boundary)
-
-	// 包含一个空的 'content' 字段
-	if content != "" {
-		err := mw.WriteField("content", content)
-		if err != nil {
-			return CreateNewMessageResponse{}, err
-		}
-	}
-	// 从远程 URL 获取图片数据
-	resp, err := http.Get(imageUrl)
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return CreateNewMessageResponse{}, fmt.Errorf("无法获取图片，状态码：%d", resp.StatusCode)
-	}
-
-	// 读取图片数据到缓冲区
-	imageData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-
-	// 从 URL 中提取文件名
-	u, err := url.Parse(imageUrl)
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-
-	filename := path.Base(u.Path)
-	if filename == "" || filename == "/" {
-		// 如果无法从 URL 中获取文件名，设置一个默认文件名
-		filename = "image"
-	}
-
-	// 确定文件的 MIME 类型
-	mimeType := resp.Header.Get("Content-Type")
-	if mimeType == "" {
-		// 如果无法从响应头获取 Content-Type，尝试从文件扩展名获取
-		ext := filepath.Ext(filename)
-		if ext != "" {
-			mimeType = mime.TypeByExtension(ext)
-		}
-		// 如果仍然无法确定，设置为 application/octet-stream
-		if mimeType == "" {
-			mimeType = "application/octet-stream"
-		}
-	}
-
-	// 创建表单文件字段 'attachments[]'，并设置适当的头部信息
-	partHeaders := make(textproto.MIMEHeader)
-	partHeaders.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachments[]"; filename="%s"`, filename))
-	partHeaders.Set("Content-Type", mimeType)
-	part, err := mw.CreatePart(partHeaders)
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-
-	// 将图片数据写入表单字段
-	_, err = part.Write(imageData)
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-
-	// 关闭 multipart 写入器，设置结束边界
-	err = mw.Close()
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-
-	// 创建 HTTP 请求
-	request, err := http.NewRequest(http.MethodPost, apiUrl, &buf)
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-
-	// 设置头部
-	request.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
-	request.Header.Add("api_access_token", agentBotToken)
-
-	// 发送请求
-	response, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		return CreateNewMessageResponse{}, fmt.Errorf("请求失败：%s", response.Status)
-	}
-
-	// 读取响应体
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return CreateNewMessageResponse{}, err
-	}
-
-	var createNewMessageResponse CreateNewMessageResponse
-
-	// 将 JSON 响应反序列化为结构体
-	if err := json.Unmarshal(body, &createNewMessageResponse); err != nil {
-		return CreateNewMessageResponse{}, err
-	}
+	return client.SendAttachmentsMessage(accountId, conversationId, agentBotToken, SendAttachmentsMessageRequest{
+		Content:     content,
+		MessageType: "outgoing",
+		Attachments: []Attachment{AttachmentFromURL(imageUrl)},
+	})
 
-	return createNewMessageResponse, nil
 }
 
 type SendNotificationRequest struct {
@@ -592,6 +523,10 @@ func (client *ChatwootClient) SendNotification(accountId int64, agentToken strin
 
 	response, err := http.DefaultClient.Do(request)
 
+	if err != nil {
+		return err
+	}
+
 	if response.StatusCode != 201 {
 		return errors.New("Request failed" + response.Status)
 	}
@@ -631,6 +566,10 @@ func (client *ChatwootClient) SendConversationTips(accountId int64, conversation
 
 	response, err := http.DefaultClient.Do(request)
 
+	if err != nil {
+		return err
+	}
+
 	if response.StatusCode != 200 {
 		return errors.New("Request failed" + response.Status)
 	}
@@ -0,0 +1,214 @@
+package chatwootclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	ConversationStatusOpen     = "open"
+	ConversationStatusResolved = "resolved"
+	ConversationStatusPending  = "pending"
+	ConversationStatusSnoozed  = "snoozed"
+)
+
+type Conversation struct {
+	ID                   int                    `json:"id"`
+	AccountID            int                    `json:"account_id,omitempty"`
+	InboxID              int                    `json:"inbox_id"`
+	Status               string                 `json:"status"`
+	Priority             string                 `json:"priority,omitempty"`
+	Labels               []string               `json:"labels,omitempty"`
+	CustomAttributes     map[string]interface{} `json:"custom_attributes,omitempty"`
+	AdditionalAttributes map[string]interface{} `json:"additional_attributes,omitempty"`
+	UnreadCount          int                    `json:"unread_count,omitempty"`
+	Meta                 ConversationMeta       `json:"meta"`
+	Messages             []Message              `json:"messages,omitempty"`
+	CreatedAt            Timestamp              `json:"created_at"`
+	LastActivityAt       Timestamp              `json:"last_activity_at"`
+}
+
+type ConversationMeta struct {
+	Sender   *Contact `json:"sender,omitempty"`
+	Assignee *Agent   `json:"assignee,omitempty"`
+	Team     *Team    `json:"team,omitempty"`
+	Channel  string   `json:"channel,omitempty"`
+}
+
+// ListConversationsRequest filters the conversations returned by ListConversations. Chatwoot returns 25
+// conversations per page, ordered by the last activity with the most recent first.
+type ListConversationsRequest struct {
+	Status       string // one of the ConversationStatus constants or "all", Chatwoot defaults to open
+	AssigneeType string // "me", "unassigned", "assigned" or "all", defaults to "all"
+	InboxID      int64
+	TeamID       int64
+	Labels       []string
+	Page         int
+}
+
+type ListConversationsResponse struct {
+	Data struct {
+		Payload []Conversation `json:"payload"`
+	} `json:"data"`
+}
+
+func (client *ChatwootClient) ListConversations(accountId int64, agentToken string, listConversationsRequest ListConversationsRequest) ([]Conversation, error) {
+	return client.ListConversationsWithContext(context.Background(), accountId, agentToken, listConversationsRequest)
+}
+
+// ListConversationsWithContext is ListConversations with a context that cancels the request.
+func (client *ChatwootClient) ListConversationsWithContext(ctx context.Context, accountId int64, agentToken string, listConversationsRequest ListConversationsRequest) ([]Conversation, error) {
+
+	query := url.Values{}
+
+	assigneeType := listConversationsRequest.AssigneeType
+	if assigneeType == "" {
+		assigneeType = "all"
+	}
+	query.Set("assignee_type", assigneeType)
+
+	if listConversationsRequest.Status != "" {
+		query.Set("status", listConversationsRequest.Status)
+	}
+
+	if listConversationsRequest.InboxID != 0 {
+		query.Set("inbox_id", strconv.FormatInt(listConversationsRequest.InboxID, 10))
+	}
+
+	if listConversationsRequest.TeamID != 0 {
+		query.Set("team_id", strconv.FormatInt(listConversationsRequest.TeamID, 10))
+	}
+
+	for _, label := range listConversationsRequest.Labels {
+		query.Add("labels[]", label)
+	}
+
+	if listConversationsRequest.Page > 0 {
+		query.Set("page", strconv.Itoa(listConversationsRequest.Page))
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations?%s", client.BaseUrl, accountId, query.Encode())
+
+	var listConversationsResponse ListConversationsResponse
+
+	if err := client.doJSONRequestWithContext(ctx, http.MethodGet, requestURL, agentToken, nil, &listConversationsResponse); err != nil {
+		return nil, err
+	}
+
+	return listConversationsResponse.Data.Payload, nil
+}
+
+type ToggleTypingStatusRequest struct {
+	TypingStatus string `json:"typing_status"`
+	IsPrivate    bool   `json:"is_private"`
+}
+
+// ToggleTypingStatus shows or hides the typing indicator of the conversation. Private typing is only shown to agents.
+func (client *ChatwootClient) ToggleTypingStatus(accountId int64, conversationId int64, agentBotToken string, on bool, private bool) error {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/toggle_typing_status", client.BaseUrl, accountId, conversationId)
+
+	typingStatus := "off"
+	if on {
+		typingStatus = "on"
+	}
+
+	return client.doJSONRequest(http.MethodPost, requestURL, agentBotToken, ToggleTypingStatusRequest{
+		TypingStatus: typingStatus,
+		IsPrivate:    private,
+	}, nil)
+}
+
+// UpdateLastSeen marks all messages of the conversation as read.
+func (client *ChatwootClient) UpdateLastSeen(accountId int64, conversationId int64, agentBotToken string) error {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/update_last_seen", client.BaseUrl, accountId, conversationId)
+
+	return client.doJSONRequest(http.MethodPost, requestURL, agentBotToken, nil, nil)
+}
+
+// DefaultTypingIndicatorRefreshInterval is the interval in which ReplyWithTypingIndicator renews the typing indicator,
+// as Chatwoot hides it after a while.
+const DefaultTypingIndicatorRefreshInterval = 10 * time.Second
+
+// TypingIndicatorOptions configure ReplyWithTypingIndicator. RefreshInterval defaults to
+// DefaultTypingIndicatorRefreshInterval. ErrorHandler is called with the errors of toggling the typing indicator,
+// which do not prevent the reply.
+type TypingIndicatorOptions struct {
+	RefreshInterval time.Duration
+	ErrorHandler    func(error)
+}
+
+// ReplyWithTypingIndicator shows the typing indicator while generate is running and sends the generated content as
+// outgoing message. The typing indicator is best effort, failing to toggle it does not prevent the reply.
+func (client *ChatwootClient) ReplyWithTypingIndicator(accountId int64, conversationId int64, agentBotToken string, typingIndicatorOptions TypingIndicatorOptions, generate func() (string, error)) (CreateNewMessageResponse, error) {
+
+	refreshInterval := typingIndicatorOptions.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultTypingIndicatorRefreshInterval
+	}
+
+	toggleTypingStatus := func(on bool) {
+		err := client.ToggleTypingStatus(accountId, conversationId, agentBotToken, on, false)
+		if err != nil && typingIndicatorOptions.ErrorHandler != nil {
+			typingIndicatorOptions.ErrorHandler(err)
+		}
+	}
+
+	toggleTypingStatus(true)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				toggleTypingStatus(true)
+			}
+		}
+	}()
+
+	content, err := generate()
+
+	close(done)
+	<-stopped
+
+	if err != nil {
+		toggleTypingStatus(false)
+		return CreateNewMessageResponse{}, err
+	}
+
+	response, err := client.CreateOutgoingMessage(accountId, conversationId, agentBotToken, content)
+
+	// hide the typing indicator, also when sending the message failed
+	toggleTypingStatus(false)
+
+	return response, err
+}
+
+type ToggleConversationStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// ToggleConversationStatus changes the status of the conversation. Agent bots hand conversations over to agents by
+// changing the status from pending to open.
+func (client *ChatwootClient) ToggleConversationStatus(accountId int64, conversationId int64, agentBotToken string, status string) error {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/toggle_status", client.BaseUrl, accountId, conversationId)
+
+	return client.doJSONRequest(http.MethodPost, requestURL, agentBotToken, ToggleConversationStatusRequest{
+		Status: status,
+	}, nil)
+}
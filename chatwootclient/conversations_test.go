@@ -0,0 +1,140 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReplyWithTypingIndicator(t *testing.T) {
+
+	var mutex sync.Mutex
+	var calls []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/api/v1/accounts/1/conversations/2/toggle_typing_status":
+			var toggleTypingStatusRequest ToggleTypingStatusRequest
+			json.NewDecoder(r.Body).Decode(&toggleTypingStatusRequest)
+			calls = append(calls, "typing "+toggleTypingStatusRequest.TypingStatus)
+		case "/api/v1/accounts/1/conversations/2/messages":
+			calls = append(calls, "message")
+			w.Write([]byte(`{"id": 5, "content": "answer"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	typingIndicatorOptions := TypingIndicatorOptions{
+		RefreshInterval: 20 * time.Millisecond,
+	}
+
+	response, err := client.ReplyWithTypingIndicator(1, 2, "", typingIndicatorOptions, func() (string, error) {
+		time.Sleep(70 * time.Millisecond)
+		return "answer", nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.ID != 5 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(calls) < 4 || calls[0] != "typing on" || calls[1] != "typing on" || calls[len(calls)-2] != "message" || calls[len(calls)-1] != "typing off" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+
+}
+
+func TestReplyWithTypingIndicatorErrors(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path == "/api/v1/accounts/1/conversations/2/messages" {
+			w.Write([]byte(`{"id": 5, "content": "answer"}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	var typingErrors []error
+
+	response, err := client.ReplyWithTypingIndicator(1, 2, "", TypingIndicatorOptions{
+		ErrorHandler: func(err error) {
+			typingErrors = append(typingErrors, err)
+		},
+	}, func() (string, error) {
+		return "answer", nil
+	})
+
+	if err != nil || response.ID != 5 {
+		t.Fatalf("expected the reply to be sent, got %+v, %v", response, err)
+	}
+
+	if len(typingErrors) != 2 {
+		t.Fatalf("expected the errors of showing and hiding the typing indicator, got %v", typingErrors)
+	}
+
+}
+
+func TestListConversations(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		query := r.URL.Query()
+
+		if r.URL.Path != "/api/v1/accounts/1/conversations" || query.Get("assignee_type") != "all" || query.Get("status") != "all" ||
+			query.Get("page") != "2" || query["labels[]"][0] != "vip" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+
+		w.Write([]byte(`{"data": {"meta": {"all_count": 26}, "payload": [{"id": 9, "status": "pending", "last_activity_at": 1709287200,
+			"messages": [{"id": 42, "content": "Where is my order?", "message_type": 0}]}]}}`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	conversations, err := client.ListConversations(1, "", ListConversationsRequest{Status: "all", Labels: []string{"vip"}, Page: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conversations) != 1 || conversations[0].ID != 9 || conversations[0].LastActivityAt.Unix() != 1709287200 ||
+		conversations[0].Messages[0].MessageType != MessageTypeIncoming {
+		t.Fatalf("unexpected conversations: %+v", conversations)
+	}
+
+}
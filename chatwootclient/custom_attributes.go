@@ -0,0 +1,375 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Models custom attributes can be defined for.
+const (
+	AttributeModelConversation = "conversation_attribute"
+	AttributeModelContact      = "contact_attribute"
+)
+
+// Display types of custom attributes, they define the type of the values.
+const (
+	AttributeTypeText     = "text"
+	AttributeTypeNumber   = "number"
+	AttributeTypeCurrency = "currency"
+	AttributeTypePercent  = "percent"
+	AttributeTypeLink     = "link"
+	AttributeTypeDate     = "date"
+	AttributeTypeList     = "list"
+	AttributeTypeCheckbox = "checkbox"
+)
+
+type CustomAttributeDefinition struct {
+	ID                   int      `json:"id"`
+	AttributeDisplayName string   `json:"attribute_display_name"`
+	AttributeDisplayType string   `json:"attribute_display_type"`
+	AttributeDescription string   `json:"attribute_description,omitempty"`
+	AttributeKey         string   `json:"attribute_key"`
+	AttributeModel       string   `json:"attribute_model"`
+	AttributeValues      []string `json:"attribute_values,omitempty"`
+	RegexPattern         string   `json:"regex_pattern,omitempty"`
+	RegexCue             string   `json:"regex_cue,omitempty"`
+	DefaultValue         string   `json:"default_value,omitempty"`
+}
+
+// CustomAttributeDefinitionRequest creates or updates a custom attribute definition. AttributeModel and
+// AttributeDisplayType take the constants of this package.
+type CustomAttributeDefinitionRequest struct {
+	AttributeDisplayName string   `json:"attribute_display_name,omitempty"`
+	AttributeDisplayType string   `json:"attribute_display_type,omitempty"`
+	AttributeDescription string   `json:"attribute_description,omitempty"`
+	AttributeKey         string   `json:"attribute_key,omitempty"`
+	AttributeModel       string   `json:"attribute_model,omitempty"`
+	AttributeValues      []string `json:"attribute_values,omitempty"`
+	RegexPattern         string   `json:"regex_pattern,omitempty"`
+	RegexCue             string   `json:"regex_cue,omitempty"`
+}
+
+func attributeModelParam(attributeModel string) string {
+	if attributeModel == AttributeModelContact {
+		return "1"
+	}
+	return "0"
+}
+
+// ListCustomAttributeDefinitions returns the custom attributes defined for conversations or contacts.
+func (client *ChatwootClient) ListCustomAttributeDefinitions(accountId int64, agentToken string, attributeModel string) ([]CustomAttributeDefinition, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/custom_attribute_definitions?attribute_model=%s", client.BaseUrl, accountId, url.QueryEscape(attributeModelParam(attributeModel)))
+
+	var customAttributeDefinitions []CustomAttributeDefinition
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &customAttributeDefinitions); err != nil {
+		return nil, err
+	}
+
+	return customAttributeDefinitions, nil
+}
+
+func (client *ChatwootClient) CreateCustomAttributeDefinition(accountId int64, agentToken string, customAttributeDefinitionRequest CustomAttributeDefinitionRequest) (CustomAttributeDefinition, error) {
+
+	if agentToken == "" {
+		return CustomAttributeDefinition{}, errors.New("agentToken is empty. Creating custom attribute definitions requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/custom_attribute_definitions", client.BaseUrl, accountId)
+
+	var customAttributeDefinition CustomAttributeDefinition
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, customAttributeDefinitionRequest, &customAttributeDefinition); err != nil {
+		return CustomAttributeDefinition{}, err
+	}
+
+	client.invalidateCustomAttributeCatalog(accountId)
+
+	return customAttributeDefinition, nil
+}
+
+func (client *ChatwootClient) UpdateCustomAttributeDefinition(accountId int64, customAttributeDefinitionId int64, agentToken string, customAttributeDefinitionRequest CustomAttributeDefinitionRequest) (CustomAttributeDefinition, error) {
+
+	if agentToken == "" {
+		return CustomAttributeDefinition{}, errors.New("agentToken is empty. Updating custom attribute definitions requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/custom_attribute_definitions/%v", client.BaseUrl, accountId, customAttributeDefinitionId)
+
+	var customAttributeDefinition CustomAttributeDefinition
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, customAttributeDefinitionRequest, &customAttributeDefinition); err != nil {
+		return CustomAttributeDefinition{}, err
+	}
+
+	client.invalidateCustomAttributeCatalog(accountId)
+
+	return customAttributeDefinition, nil
+}
+
+func (client *ChatwootClient) DeleteCustomAttributeDefinition(accountId int64, customAttributeDefinitionId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting custom attribute definitions requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/custom_attribute_definitions/%v", client.BaseUrl, accountId, customAttributeDefinitionId)
+
+	if err := client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil); err != nil {
+		return err
+	}
+
+	client.invalidateCustomAttributeCatalog(accountId)
+
+	return nil
+}
+
+type UpdateConversationCustomAttributesRequest struct {
+	CustomAttributes map[string]interface{} `json:"custom_attributes"`
+}
+
+// UpdateConversationCustomAttributes replaces the custom attributes of the conversation.
+func (client *ChatwootClient) UpdateConversationCustomAttributes(accountId int64, conversationId int64, agentToken string, customAttributes map[string]interface{}) error {
+
+	if err := client.validateCustomAttributes(accountId, agentToken, AttributeModelConversation, customAttributes); err != nil {
+		return err
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/custom_attributes", client.BaseUrl, accountId, conversationId)
+
+	return client.doJSONRequest(http.MethodPost, requestURL, agentToken, UpdateConversationCustomAttributesRequest{
+		CustomAttributes: customAttributes,
+	}, nil)
+}
+
+var ErrInvalidCustomAttribute = errors.New("invalid custom attribute")
+
+// ValidateCustomAttributes checks the custom attributes against the definitions. Every attribute has to be defined
+// and its value has to match the display type of the definition, nil values are allowed to clear an attribute.
+// The returned error wraps ErrInvalidCustomAttribute and describes all invalid attributes.
+func ValidateCustomAttributes(definitions []CustomAttributeDefinition, customAttributes map[string]interface{}) error {
+
+	definitionsByKey := make(map[string]CustomAttributeDefinition, len(definitions))
+
+	for _, definition := range definitions {
+		definitionsByKey[definition.AttributeKey] = definition
+	}
+
+	keys := make([]string, 0, len(customAttributes))
+
+	for key := range customAttributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var problems []string
+
+	for _, key := range keys {
+
+		definition, ok := definitionsByKey[key]
+
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not defined", key))
+			continue
+		}
+
+		if problem := validateCustomAttribute(definition, customAttributes[key]); problem != "" {
+			problems = append(problems, fmt.Sprintf("%s %s", key, problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidCustomAttribute, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// validateCustomAttribute returns a description of the problem if the value does not match the definition.
+func validateCustomAttribute(definition CustomAttributeDefinition, value interface{}) string {
+
+	if value == nil {
+		return ""
+	}
+
+	switch definition.AttributeDisplayType {
+
+	case AttributeTypeNumber, AttributeTypeCurrency, AttributeTypePercent:
+		switch value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+			return ""
+		}
+		return fmt.Sprintf("must be a number, got %T", value)
+
+	case AttributeTypeCheckbox:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("must be a boolean, got %T", value)
+		}
+		return ""
+
+	case AttributeTypeDate:
+		switch date := value.(type) {
+		case time.Time:
+			return ""
+		case string:
+			if _, err := time.Parse(time.RFC3339, date); err == nil {
+				return ""
+			}
+			if _, err := time.Parse("2006-01-02", date); err == nil {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be a date, got %v", value)
+	}
+
+	text, ok := value.(string)
+
+	if !ok {
+		return fmt.Sprintf("must be a string, got %T", value)
+	}
+
+	switch definition.AttributeDisplayType {
+
+	case AttributeTypeList:
+		for _, allowed := range definition.AttributeValues {
+			if allowed == text {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %s, got %q", strings.Join(definition.AttributeValues, ", "), text)
+
+	case AttributeTypeLink:
+		if u, err := url.Parse(text); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Sprintf("must be a http(s) link, got %q", text)
+		}
+		return ""
+
+	case AttributeTypeText:
+		// Chatwoot stores ruby regular expressions like /^\d+$/, patterns go does not understand are skipped
+		pattern := strings.TrimSuffix(strings.TrimPrefix(definition.RegexPattern, "/"), "/")
+		if pattern == "" {
+			return ""
+		}
+		if regex, err := regexp.Compile(pattern); err == nil && !regex.MatchString(text) {
+			if definition.RegexCue != "" {
+				return definition.RegexCue
+			}
+			return fmt.Sprintf("must match %s", definition.RegexPattern)
+		}
+	}
+
+	return ""
+}
+
+// CustomAttributeCatalog caches the custom attribute definitions of the accounts to validate custom attributes before
+// contacts are created or conversations are updated. The definitions are loaded on first use and reloaded after the
+// ttl expired or when the validation fails, at most once per MinReloadInterval. A zero ttl never expires.
+type CustomAttributeCatalog struct {
+	ttl time.Duration
+
+	mutex       sync.Mutex
+	definitions map[customAttributeCatalogKey]*reloadingCache[[]CustomAttributeDefinition]
+}
+
+type customAttributeCatalogKey struct {
+	accountId      int64
+	attributeModel string
+}
+
+func NewCustomAttributeCatalog(ttl time.Duration) *CustomAttributeCatalog {
+	return &CustomAttributeCatalog{
+		ttl:         ttl,
+		definitions: map[customAttributeCatalogKey]*reloadingCache[[]CustomAttributeDefinition]{},
+	}
+}
+
+// Invalidate drops the cached definitions of the account, they are reloaded on the next validation.
+func (customAttributeCatalog *CustomAttributeCatalog) Invalidate(accountId int64) {
+
+	customAttributeCatalog.mutex.Lock()
+	defer customAttributeCatalog.mutex.Unlock()
+
+	delete(customAttributeCatalog.definitions, customAttributeCatalogKey{accountId, AttributeModelContact})
+	delete(customAttributeCatalog.definitions, customAttributeCatalogKey{accountId, AttributeModelConversation})
+}
+
+// Validate validates the custom attributes of the given attribute model using ValidateCustomAttributes.
+func (customAttributeCatalog *CustomAttributeCatalog) Validate(client *ChatwootClient, accountId int64, agentToken string, attributeModel string, customAttributes map[string]interface{}) error {
+
+	if len(customAttributes) == 0 {
+		return nil
+	}
+
+	cache := customAttributeCatalog.cache(customAttributeCatalogKey{accountId, attributeModel})
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	definitions, err := cache.get(customAttributeCatalog.ttl, func() ([]CustomAttributeDefinition, error) {
+		return client.ListCustomAttributeDefinitions(accountId, agentToken, attributeModel)
+	}, func(definitions []CustomAttributeDefinition) bool {
+		return ValidateCustomAttributes(definitions, customAttributes) == nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return ValidateCustomAttributes(definitions, customAttributes)
+}
+
+// cache returns the cache of the account and attribute model, the catalog is only locked for the lookup, so that
+// loading the definitions of one account does not block the other accounts.
+func (customAttributeCatalog *CustomAttributeCatalog) cache(key customAttributeCatalogKey) *reloadingCache[[]CustomAttributeDefinition] {
+
+	customAttributeCatalog.mutex.Lock()
+	defer customAttributeCatalog.mutex.Unlock()
+
+	cache, ok := customAttributeCatalog.definitions[key]
+
+	if !ok {
+		cache = &reloadingCache[[]CustomAttributeDefinition]{}
+		customAttributeCatalog.definitions[key] = cache
+	}
+
+	return cache
+}
+
+func (client *ChatwootClient) validateCustomAttributes(accountId int64, agentToken string, attributeModel string, customAttributes interface{}) error {
+
+	if client.CustomAttributeCatalog == nil || customAttributes == nil {
+		return nil
+	}
+
+	attributes, ok := customAttributes.(map[string]interface{})
+
+	if !ok {
+		// e.g. structs or maps with other value types
+		customAttributesJSON, err := json.Marshal(customAttributes)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(customAttributesJSON, &attributes); err != nil {
+			return fmt.Errorf("%w: custom attributes must be an object", ErrInvalidCustomAttribute)
+		}
+	}
+
+	return client.CustomAttributeCatalog.Validate(client, accountId, agentToken, attributeModel, attributes)
+}
+
+func (client *ChatwootClient) invalidateCustomAttributeCatalog(accountId int64) {
+
+	if client.CustomAttributeCatalog != nil {
+		client.CustomAttributeCatalog.Invalidate(accountId)
+	}
+}
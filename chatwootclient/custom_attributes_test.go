@@ -0,0 +1,178 @@
+package chatwootclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateCustomAttributes(t *testing.T) {
+
+	definitions := []CustomAttributeDefinition{
+		{AttributeKey: "order_id", AttributeDisplayType: AttributeTypeText, RegexPattern: `/^#\d+$/`},
+		{AttributeKey: "cart_value", AttributeDisplayType: AttributeTypeCurrency},
+		{AttributeKey: "birthday", AttributeDisplayType: AttributeTypeDate},
+		{AttributeKey: "tier", AttributeDisplayType: AttributeTypeList, AttributeValues: []string{"silver", "gold"}},
+		{AttributeKey: "newsletter", AttributeDisplayType: AttributeTypeCheckbox},
+		{AttributeKey: "profile", AttributeDisplayType: AttributeTypeLink},
+	}
+
+	valid := map[string]interface{}{
+		"order_id":   "#1001",
+		"cart_value": 42.5,
+		"birthday":   "1990-01-31",
+		"tier":       "gold",
+		"newsletter": true,
+		"profile":    "https://example.com/jane",
+	}
+
+	if err := ValidateCustomAttributes(definitions, valid); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := map[string]interface{}{
+		"order_id":   "1001",
+		"cart_value": "42.5",
+		"birthday":   "yesterday",
+		"tier":       "platinum",
+		"newsletter": "yes",
+		"profile":    "example.com",
+		"ordr_id":    "#1001",
+	}
+
+	err := ValidateCustomAttributes(definitions, invalid)
+
+	if !errors.Is(err, ErrInvalidCustomAttribute) {
+		t.Fatalf("expected ErrInvalidCustomAttribute, got %v", err)
+	}
+
+	for key := range invalid {
+		if !strings.Contains(err.Error(), key+" ") {
+			t.Fatalf("expected %s to be reported in %v", key, err)
+		}
+	}
+
+}
+
+func TestCreateContactValidatesCustomAttributes(t *testing.T) {
+
+	createContactCalls := 0
+	listDefinitionsCalls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		switch r.URL.Path {
+		case "/api/v1/accounts/1/custom_attribute_definitions":
+			listDefinitionsCalls++
+			if r.URL.Query().Get("attribute_model") != "1" {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[{"id": 1, "attribute_key": "shop_customer_id", "attribute_display_type": "number", "attribute_model": "contact_attribute"}]`))
+		case "/api/v1/accounts/1/contacts":
+			createContactCalls++
+			w.Write([]byte(`{"payload": {"contact": {"id": 5}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl:                server.URL,
+		CustomAttributeCatalog: NewCustomAttributeCatalog(0),
+	}
+
+	_, err := client.CreateContact(1, "agent-token", CreateContactRequest{
+		InboxID:          1,
+		CustomAttributes: map[string]string{"shop_customer_id": "abc"},
+	})
+
+	if !errors.Is(err, ErrInvalidCustomAttribute) || createContactCalls != 0 {
+		t.Fatalf("expected ErrInvalidCustomAttribute, got %v", err)
+	}
+
+	response, err := client.CreateContact(1, "agent-token", CreateContactRequest{
+		InboxID:          1,
+		CustomAttributes: map[string]interface{}{"shop_customer_id": 4711},
+	})
+
+	if err != nil || response.Payload.Contact.ID != 5 || createContactCalls != 1 {
+		t.Fatalf("unexpected response %+v: %v", response, err)
+	}
+
+	if err := client.UpdateConversationCustomAttributes(1, 2, "agent-token", map[string]interface{}{"shop_customer_id": 4711}); !errors.Is(err, ErrInvalidCustomAttribute) {
+		t.Fatalf("expected conversation attributes to be validated against conversation definitions, got %v", err)
+	}
+
+	// the definitions of contacts and conversations are loaded once each, invalid attributes do not reload them
+	// within MinReloadInterval
+	if listDefinitionsCalls != 2 {
+		t.Fatalf("unexpected number of definition list calls: %d", listDefinitionsCalls)
+	}
+
+	cache := client.CustomAttributeCatalog.definitions[customAttributeCatalogKey{1, AttributeModelContact}]
+	cache.loadedAt = cache.loadedAt.Add(-MinReloadInterval)
+
+	_, err = client.CreateContact(1, "agent-token", CreateContactRequest{
+		InboxID:          1,
+		CustomAttributes: map[string]string{"shop_customer_id": "abc"},
+	})
+
+	if !errors.Is(err, ErrInvalidCustomAttribute) || listDefinitionsCalls != 3 {
+		t.Fatalf("expected the definitions to be reloaded once, %d calls: %v", listDefinitionsCalls, err)
+	}
+
+}
+
+func TestCustomAttributeCatalogLoadsAccountsIndependently(t *testing.T) {
+
+	loading := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path == "/api/v1/accounts/1/custom_attribute_definitions" {
+			close(loading)
+			<-release
+		}
+
+		w.Write([]byte(`[{"id": 1, "attribute_key": "shop_customer_id", "attribute_display_type": "number", "attribute_model": "contact_attribute"}]`))
+
+	}))
+
+	defer server.Close()
+	defer close(release)
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	customAttributeCatalog := NewCustomAttributeCatalog(0)
+	customAttributes := map[string]interface{}{"shop_customer_id": 4711}
+
+	go customAttributeCatalog.Validate(&client, 1, "agent-token", AttributeModelContact, customAttributes)
+
+	<-loading
+
+	validated := make(chan error)
+
+	go func() {
+		validated <- customAttributeCatalog.Validate(&client, 2, "agent-token", AttributeModelContact, customAttributes)
+	}()
+
+	select {
+	case err := <-validated:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the definitions of account 2 were blocked by the load of account 1")
+	}
+
+}
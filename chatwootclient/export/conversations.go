@@ -0,0 +1,196 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+// ConversationColumn is a column of a conversation export.
+type ConversationColumn struct {
+	Name  string
+	Value func(conversation chatwootclient.Conversation) interface{}
+}
+
+// DefaultConversationColumns are the columns exported if no columns are given.
+var DefaultConversationColumns = []ConversationColumn{
+	{"id", func(conversation chatwootclient.Conversation) interface{} {
+		return conversation.ID
+	}},
+	{"inbox_id", func(conversation chatwootclient.Conversation) interface{} {
+		return conversation.InboxID
+	}},
+	{"status", func(conversation chatwootclient.Conversation) interface{} {
+		return conversation.Status
+	}},
+	{"priority", func(conversation chatwootclient.Conversation) interface{} {
+		return conversation.Priority
+	}},
+	{"channel", func(conversation chatwootclient.Conversation) interface{} {
+		return conversation.Meta.Channel
+	}},
+	{"contact_name", func(conversation chatwootclient.Conversation) interface{} {
+		if conversation.Meta.Sender == nil {
+			return nil
+		}
+		return conversation.Meta.Sender.Name
+	}},
+	{"contact_email", func(conversation chatwootclient.Conversation) interface{} {
+		if conversation.Meta.Sender == nil {
+			return nil
+		}
+		return conversation.Meta.Sender.Email
+	}},
+	{"assignee", func(conversation chatwootclient.Conversation) interface{} {
+		if conversation.Meta.Assignee == nil {
+			return nil
+		}
+		return conversation.Meta.Assignee.Name
+	}},
+	{"team", func(conversation chatwootclient.Conversation) interface{} {
+		if conversation.Meta.Team == nil {
+			return nil
+		}
+		return conversation.Meta.Team.Name
+	}},
+	{"labels", func(conversation chatwootclient.Conversation) interface{} {
+		if conversation.Labels == nil {
+			return []string{}
+		}
+		return conversation.Labels
+	}},
+	{"unread_count", func(conversation chatwootclient.Conversation) interface{} {
+		return conversation.UnreadCount
+	}},
+	{"created_at", func(conversation chatwootclient.Conversation) interface{} {
+		return conversation.CreatedAt.Time
+	}},
+	{"last_activity_at", func(conversation chatwootclient.Conversation) interface{} {
+		return conversation.LastActivityAt.Time
+	}},
+}
+
+// CustomAttributeColumn returns a column with the value of the custom attribute of the conversation.
+func CustomAttributeColumn(key string) ConversationColumn {
+	return ConversationColumn{
+		Name: key,
+		Value: func(conversation chatwootclient.Conversation) interface{} {
+			return conversation.CustomAttributes[key]
+		},
+	}
+}
+
+// ConversationColumns returns the default conversation columns with the given names in the given order.
+func ConversationColumns(names ...string) ([]ConversationColumn, error) {
+
+	columns := make([]ConversationColumn, 0, len(names))
+
+	for _, name := range names {
+		column, ok := findConversationColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown conversation column %q", name)
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+func findConversationColumn(name string) (ConversationColumn, bool) {
+	for _, column := range DefaultConversationColumns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return ConversationColumn{}, false
+}
+
+// ConversationExporter writes conversations as rows. Call Flush after the last conversation.
+//
+// CSV values that spreadsheets would evaluate as formula are prefixed with a single quote, set RawValues to write
+// them unchanged, e.g. if the CSV is loaded into a database.
+type ConversationExporter struct {
+	RawValues bool
+
+	columns   []ConversationColumn
+	rowWriter *rowWriter
+}
+
+// NewConversationExporter returns an exporter writing the columns in the format, DefaultConversationColumns if no
+// columns are given.
+func NewConversationExporter(writer io.Writer, format Format, columns ...ConversationColumn) (*ConversationExporter, error) {
+
+	if len(columns) == 0 {
+		columns = DefaultConversationColumns
+	}
+
+	columnNames := make([]string, len(columns))
+
+	for i, column := range columns {
+		columnNames[i] = column.Name
+	}
+
+	rowWriter, err := newRowWriter(writer, format, columnNames)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConversationExporter{
+		columns:   columns,
+		rowWriter: rowWriter,
+	}, nil
+}
+
+func (exporter *ConversationExporter) Write(conversations ...chatwootclient.Conversation) error {
+
+	for _, conversation := range conversations {
+
+		values := make([]interface{}, len(exporter.columns))
+
+		for i, column := range exporter.columns {
+			values[i] = column.Value(conversation)
+		}
+
+		if err := exporter.rowWriter.writeRow(values, exporter.RawValues); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteAll lists the conversations page by page, starting at the page of the request, and writes them until a page
+// is empty. It returns the number of written conversations.
+func (exporter *ConversationExporter) WriteAll(client *chatwootclient.ChatwootClient, accountId int64, agentToken string, listConversationsRequest chatwootclient.ListConversationsRequest) (int, error) {
+
+	written := 0
+
+	if listConversationsRequest.Page < 1 {
+		listConversationsRequest.Page = 1
+	}
+
+	for {
+		conversations, err := client.ListConversations(accountId, agentToken, listConversationsRequest)
+
+		if err != nil {
+			return written, err
+		}
+
+		if len(conversations) == 0 {
+			return written, nil
+		}
+
+		if err := exporter.Write(conversations...); err != nil {
+			return written, err
+		}
+
+		written += len(conversations)
+		listConversationsRequest.Page++
+	}
+}
+
+func (exporter *ConversationExporter) Flush() error {
+	return exporter.rowWriter.flush()
+}
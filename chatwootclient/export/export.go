@@ -0,0 +1,185 @@
+// Package export writes report time series and conversation lists as CSV or newline delimited JSON, e.g. to load
+// them into spreadsheets or a data warehouse. The columns are configurable, either by name from the default columns or
+// with custom value funcs.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// rowWriter writes rows of values in the format, the CSV header is written before the first row.
+type rowWriter struct {
+	writer        io.Writer
+	format        Format
+	columnNames   []string
+	csvWriter     *csv.Writer
+	headerWritten bool
+}
+
+func newRowWriter(writer io.Writer, format Format, columnNames []string) (*rowWriter, error) {
+
+	if len(columnNames) == 0 {
+		return nil, fmt.Errorf("no columns to export")
+	}
+
+	rowWriter := &rowWriter{
+		writer:      writer,
+		format:      format,
+		columnNames: columnNames,
+	}
+
+	switch format {
+	case FormatCSV:
+		rowWriter.csvWriter = csv.NewWriter(writer)
+	case FormatNDJSON:
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	return rowWriter, nil
+}
+
+// writeRow writes the values, text values of CSV rows are escaped with escapeFormula unless rawValues is set.
+func (rowWriter *rowWriter) writeRow(values []interface{}, rawValues bool) error {
+
+	if rowWriter.format == FormatNDJSON {
+		return rowWriter.writeJSON(values)
+	}
+
+	if !rowWriter.headerWritten {
+		if err := rowWriter.csvWriter.Write(rowWriter.columnNames); err != nil {
+			return err
+		}
+		rowWriter.headerWritten = true
+	}
+
+	record := make([]string, len(values))
+
+	for i, value := range values {
+		formatted, text := formatCSV(value)
+		if text && !rawValues {
+			formatted = escapeFormula(formatted)
+		}
+		record[i] = formatted
+	}
+
+	return rowWriter.csvWriter.Write(record)
+}
+
+// writeJSON writes the values as object with the keys in the order of the columns.
+func (rowWriter *rowWriter) writeJSON(values []interface{}) error {
+
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('{')
+
+	for i, value := range values {
+
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		name, _ := json.Marshal(rowWriter.columnNames[i])
+
+		if timestamp, ok := value.(time.Time); ok && timestamp.IsZero() {
+			value = nil
+		}
+
+		encoded, err := json.Marshal(value)
+
+		if err != nil {
+			return fmt.Errorf("failed to encode column %s: %w", rowWriter.columnNames[i], err)
+		}
+
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(encoded)
+	}
+
+	buffer.WriteString("}\n")
+
+	_, err := rowWriter.writer.Write(buffer.Bytes())
+
+	return err
+}
+
+// flush writes the buffered CSV rows, the header is written even if there are no rows.
+func (rowWriter *rowWriter) flush() error {
+
+	if rowWriter.csvWriter == nil {
+		return nil
+	}
+
+	if !rowWriter.headerWritten {
+		if err := rowWriter.csvWriter.Write(rowWriter.columnNames); err != nil {
+			return err
+		}
+		rowWriter.headerWritten = true
+	}
+
+	rowWriter.csvWriter.Flush()
+
+	return rowWriter.csvWriter.Error()
+}
+
+// formatCSV formats the value and reports whether it is text, e.g. a name or custom attribute, rather than a number,
+// bool or timestamp. Numbers of any integer or float type are not text, also json.Number and the float64 values of
+// decoded custom attributes.
+func formatCSV(value interface{}) (string, bool) {
+
+	switch value := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	case time.Time:
+		if value.IsZero() {
+			return "", false
+		}
+		return value.Format(time.RFC3339), false
+	case json.Number:
+		return value.String(), false
+	case bool:
+		return strconv.FormatBool(value), false
+	case []string:
+		return strings.Join(value, ";"), true
+	}
+
+	// e.g. chatwootclient.ReportValue
+	switch number := reflect.ValueOf(value); number.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(number.Int(), 10), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(number.Uint(), 10), false
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(number.Float(), 'f', -1, number.Type().Bits()), false
+	}
+
+	return fmt.Sprint(value), true
+}
+
+// escapeFormula prefixes text starting with a character that spreadsheets interpret as the start of a formula with a
+// single quote, so that values like contact names entered by customers are shown as text instead of being evaluated.
+func escapeFormula(value string) string {
+
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
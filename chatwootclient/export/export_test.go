@@ -0,0 +1,215 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+func TestReportExporter(t *testing.T) {
+
+	series := chatwootclient.ReportTimeSeries{
+		Metric:  chatwootclient.ReportMetricAvgFirstResponseTime,
+		Type:    chatwootclient.ReportTypeInbox,
+		ID:      3,
+		GroupBy: chatwootclient.ReportGroupByDay,
+		Points: []chatwootclient.ReportPoint{
+			{Timestamp: chatwootclient.Timestamp{Time: time.Unix(1709251200, 0).UTC()}, Value: 90.5, Count: 4},
+			{Timestamp: chatwootclient.Timestamp{Time: time.Unix(1709337600, 0).UTC()}, Value: 0},
+		},
+	}
+
+	var csvOutput bytes.Buffer
+
+	exporter, err := NewReportExporter(&csvOutput, FormatCSV)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := exporter.Write(series); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := exporter.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "metric,type,id,group_by,timestamp,value,count\n" +
+		"avg_first_response_time,inbox,3,day,2024-03-01T00:00:00Z,90.5,4\n" +
+		"avg_first_response_time,inbox,3,day,2024-03-02T00:00:00Z,0,0\n"
+
+	if csvOutput.String() != expected {
+		t.Fatalf("unexpected csv:\n%s", csvOutput.String())
+	}
+
+	columns, err := ReportColumns("timestamp", "value")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jsonOutput bytes.Buffer
+
+	exporter, _ = NewReportExporter(&jsonOutput, FormatNDJSON, columns...)
+	exporter.Write(series)
+	exporter.Flush()
+
+	expected = `{"timestamp":"2024-03-01T00:00:00Z","value":90.5}` + "\n" + `{"timestamp":"2024-03-02T00:00:00Z","value":0}` + "\n"
+
+	if jsonOutput.String() != expected {
+		t.Fatalf("unexpected ndjson:\n%s", jsonOutput.String())
+	}
+
+	if _, err := ReportColumns("timestamp", "median"); err == nil {
+		t.Fatal("expected an error for an unknown column")
+	}
+
+	if _, err := NewReportExporter(&jsonOutput, "xlsx"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+
+}
+
+func TestConversationExporter(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`{"data": {"payload": [
+				{"id": 9, "inbox_id": 3, "status": "open", "labels": ["vip", "order"], "custom_attributes": {"order_id": "A-1, A-2"},
+					"meta": {"sender": {"id": 7, "name": "Jane Doe", "email": "jane@example.com"}}, "created_at": 1709251200}
+			]}}`))
+		case "2":
+			w.Write([]byte(`{"data": {"payload": [{"id": 10, "inbox_id": 3, "status": "resolved", "meta": {"assignee": {"id": 2, "name": "Max"}}}]}}`))
+		default:
+			w.Write([]byte(`{"data": {"payload": []}}`))
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := chatwootclient.NewChatwootClient(server.URL)
+
+	columns, err := ConversationColumns("id", "status", "contact_name", "assignee", "labels", "created_at")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var csvOutput bytes.Buffer
+
+	exporter, err := NewConversationExporter(&csvOutput, FormatCSV, append(columns, CustomAttributeColumn("order_id"))...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := exporter.WriteAll(&client, 1, "agent-token", chatwootclient.ListConversationsRequest{Status: "all"})
+
+	if err != nil || written != 2 {
+		t.Fatalf("unexpected export of %d conversations: %v", written, err)
+	}
+
+	if err := exporter.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "id,status,contact_name,assignee,labels,created_at,order_id\n" +
+		"9,open,Jane Doe,,vip;order,2024-03-01T00:00:00Z,\"A-1, A-2\"\n" +
+		"10,resolved,,Max,,,\n"
+
+	if csvOutput.String() != expected {
+		t.Fatalf("unexpected csv:\n%s", csvOutput.String())
+	}
+
+	var jsonOutput bytes.Buffer
+
+	exporter, _ = NewConversationExporter(&jsonOutput, FormatNDJSON, columns...)
+	exporter.Write(chatwootclient.Conversation{ID: 10, Status: "resolved"})
+	exporter.Flush()
+
+	expected = `{"id":10,"status":"resolved","contact_name":null,"assignee":null,"labels":[],"created_at":null}` + "\n"
+
+	if jsonOutput.String() != expected {
+		t.Fatalf("unexpected ndjson:\n%s", jsonOutput.String())
+	}
+
+}
+
+func TestConversationExporterEscapesFormulas(t *testing.T) {
+
+	conversation := chatwootclient.Conversation{
+		ID:               11,
+		Labels:           []string{"-vip"},
+		CustomAttributes: map[string]interface{}{"order_id": "@SUM(A1:A9)", "discount": -5.0},
+		Meta: chatwootclient.ConversationMeta{
+			Sender: &chatwootclient.Contact{Name: `=HYPERLINK("http://attacker.example","Jane")`, Email: "+jane@example.com"},
+		},
+	}
+
+	columns, _ := ConversationColumns("id", "contact_name", "contact_email", "labels")
+	columns = append(columns, CustomAttributeColumn("order_id"), CustomAttributeColumn("discount"))
+
+	var csvOutput bytes.Buffer
+
+	exporter, _ := NewConversationExporter(&csvOutput, FormatCSV, columns...)
+	exporter.Write(conversation)
+	exporter.Flush()
+
+	expected := "id,contact_name,contact_email,labels,order_id,discount\n" +
+		`11,"'=HYPERLINK(""http://attacker.example"",""Jane"")",'+jane@example.com,'-vip,'@SUM(A1:A9),-5` + "\n"
+
+	if csvOutput.String() != expected {
+		t.Fatalf("unexpected csv:\n%s", csvOutput.String())
+	}
+
+	csvOutput.Reset()
+
+	exporter, _ = NewConversationExporter(&csvOutput, FormatCSV, columns[3])
+	exporter.RawValues = true
+	exporter.Write(conversation)
+	exporter.Flush()
+
+	if csvOutput.String() != "labels\n-vip\n" {
+		t.Fatalf("expected raw values, got:\n%s", csvOutput.String())
+	}
+
+}
+
+func TestConversationExporterWritesNumbersUnescaped(t *testing.T) {
+
+	var conversation chatwootclient.Conversation
+
+	json.Unmarshal([]byte(`{"id": 12, "custom_attributes": {"amount": -12.5, "quantity": -3}}`), &conversation)
+
+	columns := []ConversationColumn{CustomAttributeColumn("amount"), CustomAttributeColumn("quantity")}
+
+	for _, value := range []interface{}{int32(-1), uint64(7), float32(-0.5), json.Number("-2.5"), chatwootclient.ReportValue(-1.5)} {
+		value := value
+		columns = append(columns, ConversationColumn{fmt.Sprintf("%T", value), func(conversation chatwootclient.Conversation) interface{} {
+			return value
+		}})
+	}
+
+	var csvOutput bytes.Buffer
+
+	exporter, _ := NewConversationExporter(&csvOutput, FormatCSV, columns...)
+	exporter.Write(conversation)
+	exporter.Flush()
+
+	expected := "amount,quantity,int32,uint64,float32,json.Number,chatwootclient.ReportValue\n-12.5,-3,-1,7,-0.5,-2.5,-1.5\n"
+
+	if csvOutput.String() != expected {
+		t.Fatalf("unexpected csv:\n%s", csvOutput.String())
+	}
+
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+// ReportColumn is a column of a report export, Value returns the value of the column for a point of the series.
+type ReportColumn struct {
+	Name  string
+	Value func(series chatwootclient.ReportTimeSeries, point chatwootclient.ReportPoint) interface{}
+}
+
+// DefaultReportColumns are the columns exported if no columns are given.
+var DefaultReportColumns = []ReportColumn{
+	{"metric", func(series chatwootclient.ReportTimeSeries, point chatwootclient.ReportPoint) interface{} {
+		return string(series.Metric)
+	}},
+	{"type", func(series chatwootclient.ReportTimeSeries, point chatwootclient.ReportPoint) interface{} {
+		return string(series.Type)
+	}},
+	{"id", func(series chatwootclient.ReportTimeSeries, point chatwootclient.ReportPoint) interface{} {
+		return series.ID
+	}},
+	{"group_by", func(series chatwootclient.ReportTimeSeries, point chatwootclient.ReportPoint) interface{} {
+		return string(series.GroupBy)
+	}},
+	{"timestamp", func(series chatwootclient.ReportTimeSeries, point chatwootclient.ReportPoint) interface{} {
+		return point.Timestamp.Time
+	}},
+	{"value", func(series chatwootclient.ReportTimeSeries, point chatwootclient.ReportPoint) interface{} {
+		return point.Value
+	}},
+	{"count", func(series chatwootclient.ReportTimeSeries, point chatwootclient.ReportPoint) interface{} {
+		return point.Count
+	}},
+}
+
+// ReportColumns returns the default report columns with the given names in the given order.
+func ReportColumns(names ...string) ([]ReportColumn, error) {
+
+	columns := make([]ReportColumn, 0, len(names))
+
+	for _, name := range names {
+		column, ok := findReportColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown report column %q", name)
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+func findReportColumn(name string) (ReportColumn, bool) {
+	for _, column := range DefaultReportColumns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return ReportColumn{}, false
+}
+
+// ReportExporter writes the points of report time series as rows. Call Flush after the last series.
+//
+// CSV values that spreadsheets would evaluate as formula are prefixed with a single quote, set RawValues to write
+// them unchanged, e.g. if the CSV is loaded into a database.
+type ReportExporter struct {
+	RawValues bool
+
+	columns   []ReportColumn
+	rowWriter *rowWriter
+}
+
+// NewReportExporter returns an exporter writing the columns in the format, DefaultReportColumns if no columns are
+// given.
+func NewReportExporter(writer io.Writer, format Format, columns ...ReportColumn) (*ReportExporter, error) {
+
+	if len(columns) == 0 {
+		columns = DefaultReportColumns
+	}
+
+	columnNames := make([]string, len(columns))
+
+	for i, column := range columns {
+		columnNames[i] = column.Name
+	}
+
+	rowWriter, err := newRowWriter(writer, format, columnNames)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReportExporter{
+		columns:   columns,
+		rowWriter: rowWriter,
+	}, nil
+}
+
+// Write writes a row per point of the series, series of several metrics can be written to the same export.
+func (exporter *ReportExporter) Write(series ...chatwootclient.ReportTimeSeries) error {
+
+	for _, reportTimeSeries := range series {
+		for _, point := range reportTimeSeries.Points {
+
+			values := make([]interface{}, len(exporter.columns))
+
+			for i, column := range exporter.columns {
+				values[i] = column.Value(reportTimeSeries, point)
+			}
+
+			if err := exporter.rowWriter.writeRow(values, exporter.RawValues); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (exporter *ReportExporter) Flush() error {
+	return exporter.rowWriter.flush()
+}
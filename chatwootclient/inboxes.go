@@ -0,0 +1,311 @@
+package chatwootclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Channel types as returned in Inbox.ChannelType.
+const (
+	ChannelTypeWebWidget = "Channel::WebWidget"
+	ChannelTypeApi       = "Channel::Api"
+	ChannelTypeEmail     = "Channel::Email"
+	ChannelTypeWhatsapp  = "Channel::Whatsapp"
+	ChannelTypeFacebook  = "Channel::FacebookPage"
+	ChannelTypeTelegram  = "Channel::Telegram"
+	ChannelTypeSms       = "Channel::Sms"
+	ChannelTypeLine      = "Channel::Line"
+)
+
+// WorkingHour describes the business hours of a weekday, DayOfWeek starts with 0 for sunday.
+type WorkingHour struct {
+	DayOfWeek    int  `json:"day_of_week"`
+	ClosedAllDay bool `json:"closed_all_day"`
+	OpenAllDay   bool `json:"open_all_day"`
+	OpenHour     int  `json:"open_hour"`
+	OpenMinutes  int  `json:"open_minutes"`
+	CloseHour    int  `json:"close_hour"`
+	CloseMinutes int  `json:"close_minutes"`
+}
+
+type Inbox struct {
+	ID                         int               `json:"id"`
+	Name                       string            `json:"name"`
+	AvatarUrl                  string            `json:"avatar_url,omitempty"`
+	ChannelID                  int               `json:"channel_id"`
+	ChannelType                string            `json:"channel_type"`
+	GreetingEnabled            bool              `json:"greeting_enabled"`
+	GreetingMessage            string            `json:"greeting_message,omitempty"`
+	EnableEmailCollect         bool              `json:"enable_email_collect"`
+	CsatSurveyEnabled          bool              `json:"csat_survey_enabled"`
+	EnableAutoAssignment       bool              `json:"enable_auto_assignment"`
+	AllowMessagesAfterResolved bool              `json:"allow_messages_after_resolved"`
+	LockToSingleConversation   bool              `json:"lock_to_single_conversation"`
+	WorkingHoursEnabled        bool              `json:"working_hours_enabled"`
+	WorkingHours               []WorkingHour     `json:"working_hours,omitempty"`
+	OutOfOfficeMessage         string            `json:"out_of_office_message,omitempty"`
+	Timezone                   string            `json:"timezone,omitempty"`
+	WebsiteUrl                 string            `json:"website_url,omitempty"`
+	WebsiteToken               string            `json:"website_token,omitempty"`
+	WelcomeTitle               string            `json:"welcome_title,omitempty"`
+	WelcomeTagline             string            `json:"welcome_tagline,omitempty"`
+	WidgetColor                string            `json:"widget_color,omitempty"`
+	WebhookUrl                 string            `json:"webhook_url,omitempty"`
+	InboxIdentifier            string            `json:"inbox_identifier,omitempty"`
+	Email                      string            `json:"email,omitempty"`
+	ForwardToEmail             string            `json:"forward_to_email,omitempty"`
+	PhoneNumber                string            `json:"phone_number,omitempty"`
+	Provider                   string            `json:"provider,omitempty"`
+	MessageTemplates           []MessageTemplate `json:"message_templates,omitempty"`
+}
+
+// InboxChannel configures the channel of an inbox. Use NewWebsiteChannel, NewApiChannel or NewEmailChannel to
+// create the configuration of the respective channel type.
+type InboxChannel struct {
+	Type           string `json:"type,omitempty"`
+	WebsiteUrl     string `json:"website_url,omitempty"`
+	WelcomeTitle   string `json:"welcome_title,omitempty"`
+	WelcomeTagline string `json:"welcome_tagline,omitempty"`
+	WidgetColor    string `json:"widget_color,omitempty"`
+	WebhookUrl     string `json:"webhook_url,omitempty"`
+	HmacMandatory  *bool  `json:"hmac_mandatory,omitempty"`
+	Email          string `json:"email,omitempty"`
+}
+
+func NewWebsiteChannel(websiteUrl string, welcomeTitle string, welcomeTagline string, widgetColor string) InboxChannel {
+	return InboxChannel{
+		Type:           "web_widget",
+		WebsiteUrl:     websiteUrl,
+		WelcomeTitle:   welcomeTitle,
+		WelcomeTagline: welcomeTagline,
+		WidgetColor:    widgetColor,
+	}
+}
+
+func NewApiChannel(webhookUrl string) InboxChannel {
+	return InboxChannel{
+		Type:       "api",
+		WebhookUrl: webhookUrl,
+	}
+}
+
+func NewEmailChannel(email string) InboxChannel {
+	return InboxChannel{
+		Type:  "email",
+		Email: email,
+	}
+}
+
+type CreateInboxRequest struct {
+	Name                       string        `json:"name"`
+	Channel                    InboxChannel  `json:"channel"`
+	GreetingEnabled            bool          `json:"greeting_enabled,omitempty"`
+	GreetingMessage            string        `json:"greeting_message,omitempty"`
+	EnableEmailCollect         bool          `json:"enable_email_collect,omitempty"`
+	CsatSurveyEnabled          bool          `json:"csat_survey_enabled,omitempty"`
+	EnableAutoAssignment       *bool         `json:"enable_auto_assignment,omitempty"`
+	AllowMessagesAfterResolved *bool         `json:"allow_messages_after_resolved,omitempty"`
+	LockToSingleConversation   bool          `json:"lock_to_single_conversation,omitempty"`
+	WorkingHoursEnabled        bool          `json:"working_hours_enabled,omitempty"`
+	WorkingHours               []WorkingHour `json:"working_hours,omitempty"`
+	OutOfOfficeMessage         string        `json:"out_of_office_message,omitempty"`
+	Timezone                   string        `json:"timezone,omitempty"`
+}
+
+// UpdateInboxRequest only changes the fields that are set.
+type UpdateInboxRequest struct {
+	Name                       string        `json:"name,omitempty"`
+	Channel                    *InboxChannel `json:"channel,omitempty"`
+	GreetingEnabled            *bool         `json:"greeting_enabled,omitempty"`
+	GreetingMessage            *string       `json:"greeting_message,omitempty"`
+	EnableEmailCollect         *bool         `json:"enable_email_collect,omitempty"`
+	CsatSurveyEnabled          *bool         `json:"csat_survey_enabled,omitempty"`
+	EnableAutoAssignment       *bool         `json:"enable_auto_assignment,omitempty"`
+	AllowMessagesAfterResolved *bool         `json:"allow_messages_after_resolved,omitempty"`
+	LockToSingleConversation   *bool         `json:"lock_to_single_conversation,omitempty"`
+	WorkingHoursEnabled        *bool         `json:"working_hours_enabled,omitempty"`
+	WorkingHours               []WorkingHour `json:"working_hours,omitempty"`
+	OutOfOfficeMessage         *string       `json:"out_of_office_message,omitempty"`
+	Timezone                   string        `json:"timezone,omitempty"`
+}
+
+type ListInboxesResponse struct {
+	Payload []Inbox `json:"payload"`
+}
+
+func (client *ChatwootClient) ListInboxes(accountId int64, agentToken string) ([]Inbox, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/inboxes", client.BaseUrl, accountId)
+
+	var listInboxesResponse ListInboxesResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &listInboxesResponse); err != nil {
+		return nil, err
+	}
+
+	return listInboxesResponse.Payload, nil
+}
+
+func (client *ChatwootClient) GetInbox(accountId int64, inboxId int64, agentToken string) (Inbox, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/inboxes/%v", client.BaseUrl, accountId, inboxId)
+
+	var inbox Inbox
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &inbox); err != nil {
+		return Inbox{}, err
+	}
+
+	return inbox, nil
+}
+
+func (client *ChatwootClient) CreateInbox(accountId int64, agentToken string, createInboxRequest CreateInboxRequest) (Inbox, error) {
+
+	if agentToken == "" {
+		return Inbox{}, errors.New("agentToken is empty. Creating inboxes requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/inboxes", client.BaseUrl, accountId)
+
+	var inbox Inbox
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, createInboxRequest, &inbox); err != nil {
+		return Inbox{}, err
+	}
+
+	return inbox, nil
+}
+
+func (client *ChatwootClient) UpdateInbox(accountId int64, inboxId int64, agentToken string, updateInboxRequest UpdateInboxRequest) (Inbox, error) {
+
+	if agentToken == "" {
+		return Inbox{}, errors.New("agentToken is empty. Updating inboxes requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/inboxes/%v", client.BaseUrl, accountId, inboxId)
+
+	var inbox Inbox
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, updateInboxRequest, &inbox); err != nil {
+		return Inbox{}, err
+	}
+
+	return inbox, nil
+}
+
+type InboxMembersRequest struct {
+	InboxID int64   `json:"inbox_id"`
+	UserIDs []int64 `json:"user_ids"`
+}
+
+type InboxMembersResponse struct {
+	Payload []Agent `json:"payload"`
+}
+
+func (client *ChatwootClient) ListInboxMembers(accountId int64, inboxId int64, agentToken string) ([]Agent, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/inbox_members/%v", client.BaseUrl, accountId, inboxId)
+
+	var inboxMembersResponse InboxMembersResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &inboxMembersResponse); err != nil {
+		return nil, err
+	}
+
+	return inboxMembersResponse.Payload, nil
+}
+
+// AddInboxMembers adds the agents with the given user ids to the inbox and returns all members of the inbox.
+func (client *ChatwootClient) AddInboxMembers(accountId int64, inboxId int64, agentToken string, userIds []int64) ([]Agent, error) {
+
+	return client.changeInboxMembers(http.MethodPost, accountId, inboxId, agentToken, userIds)
+}
+
+// RemoveInboxMembers removes the agents with the given user ids from the inbox.
+func (client *ChatwootClient) RemoveInboxMembers(accountId int64, inboxId int64, agentToken string, userIds []int64) error {
+
+	_, err := client.changeInboxMembers(http.MethodDelete, accountId, inboxId, agentToken, userIds)
+
+	return err
+}
+
+// SetInboxMembers replaces the members of the inbox with the agents with the given user ids.
+func (client *ChatwootClient) SetInboxMembers(accountId int64, inboxId int64, agentToken string, userIds []int64) ([]Agent, error) {
+
+	return client.changeInboxMembers(http.MethodPatch, accountId, inboxId, agentToken, userIds)
+}
+
+func (client *ChatwootClient) changeInboxMembers(method string, accountId int64, inboxId int64, agentToken string, userIds []int64) ([]Agent, error) {
+
+	if agentToken == "" {
+		return nil, errors.New("agentToken is empty. Changing inbox members requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/inbox_members", client.BaseUrl, accountId)
+
+	var inboxMembersResponse InboxMembersResponse
+
+	err := client.doJSONRequest(method, requestURL, agentToken, InboxMembersRequest{
+		InboxID: inboxId,
+		UserIDs: userIds,
+	}, &inboxMembersResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return inboxMembersResponse.Payload, nil
+}
+
+type InboxAgentBotResponse struct {
+	AgentBot *AgentBot `json:"agent_bot"`
+}
+
+// GetInboxAgentBot returns the agent bot connected to the inbox or nil if the inbox is handled by humans only.
+func (client *ChatwootClient) GetInboxAgentBot(accountId int64, inboxId int64, agentToken string) (*AgentBot, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/inboxes/%v/agent_bot", client.BaseUrl, accountId, inboxId)
+
+	var inboxAgentBotResponse InboxAgentBotResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &inboxAgentBotResponse); err != nil {
+		return nil, err
+	}
+
+	// Chatwoot renders an empty object if no agent bot is connected
+	if inboxAgentBotResponse.AgentBot == nil || inboxAgentBotResponse.AgentBot.ID == 0 {
+		return nil, nil
+	}
+
+	return inboxAgentBotResponse.AgentBot, nil
+}
+
+type SetInboxAgentBotRequest struct {
+	AgentBot *int64 `json:"agent_bot"`
+}
+
+// SetInboxAgentBot connects the agent bot to the inbox, replacing the currently connected bot.
+func (client *ChatwootClient) SetInboxAgentBot(accountId int64, inboxId int64, agentToken string, agentBotId int64) error {
+
+	return client.setInboxAgentBot(accountId, inboxId, agentToken, &agentBotId)
+}
+
+// ClearInboxAgentBot disconnects the agent bot from the inbox, so that conversations are handled by humans only.
+func (client *ChatwootClient) ClearInboxAgentBot(accountId int64, inboxId int64, agentToken string) error {
+
+	return client.setInboxAgentBot(accountId, inboxId, agentToken, nil)
+}
+
+func (client *ChatwootClient) setInboxAgentBot(accountId int64, inboxId int64, agentToken string, agentBotId *int64) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Setting the agent bot of an inbox requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/inboxes/%v/set_agent_bot", client.BaseUrl, accountId, inboxId)
+
+	return client.doJSONRequest(http.MethodPost, requestURL, agentToken, SetInboxAgentBotRequest{
+		AgentBot: agentBotId,
+	}, nil)
+}
@@ -0,0 +1,156 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateInbox(t *testing.T) {
+
+	var createInboxRequest map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/accounts/1/inboxes" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(&createInboxRequest)
+
+		w.Write([]byte(`{"id": 9, "name": "Store 42", "channel_type": "Channel::WebWidget", "working_hours_enabled": true,
+			"working_hours": [{"day_of_week": 1, "open_hour": 9, "open_minutes": 0, "close_hour": 17, "close_minutes": 30}]}`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	inbox, err := client.CreateInbox(1, "agent-token", CreateInboxRequest{
+		Name:    "Store 42",
+		Channel: NewWebsiteChannel("https://store42.example.com", "Hi", "", "#ff0000"),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel, _ := createInboxRequest["channel"].(map[string]interface{})
+
+	if channel["type"] != "web_widget" || channel["website_url"] != "https://store42.example.com" {
+		t.Fatalf("unexpected request: %v", createInboxRequest)
+	}
+
+	if inbox.ID != 9 || inbox.ChannelType != ChannelTypeWebWidget || len(inbox.WorkingHours) != 1 || inbox.WorkingHours[0].CloseMinutes != 30 {
+		t.Fatalf("unexpected inbox: %+v", inbox)
+	}
+
+	if _, err := client.CreateInbox(1, "", CreateInboxRequest{Name: "Store 42"}); err == nil {
+		t.Fatal("expected an error without agent token")
+	}
+
+}
+
+func TestInboxMembers(t *testing.T) {
+
+	var inboxMembersRequest InboxMembersRequest
+	var method string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		switch r.URL.Path {
+		case "/api/v1/accounts/1/inbox_members/9":
+			w.Write([]byte(`{"payload": [{"id": 3, "name": "Jane", "availability_status": "online"}]}`))
+		case "/api/v1/accounts/1/inbox_members":
+			method = r.Method
+			json.NewDecoder(r.Body).Decode(&inboxMembersRequest)
+			if r.Method != http.MethodDelete {
+				w.Write([]byte(`{"payload": [{"id": 3, "name": "Jane"}, {"id": 4, "name": "John"}]}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	members, err := client.ListInboxMembers(1, 9, "agent-token")
+
+	if err != nil || len(members) != 1 || members[0].AvailabilityStatus != "online" {
+		t.Fatalf("unexpected members %+v: %v", members, err)
+	}
+
+	members, err = client.AddInboxMembers(1, 9, "agent-token", []int64{4})
+
+	if err != nil || len(members) != 2 || method != http.MethodPost || inboxMembersRequest.InboxID != 9 || inboxMembersRequest.UserIDs[0] != 4 {
+		t.Fatalf("unexpected members %+v: %v", members, err)
+	}
+
+	if err := client.RemoveInboxMembers(1, 9, "agent-token", []int64{4}); err != nil || method != http.MethodDelete {
+		t.Fatalf("unexpected result of removing members: %v", err)
+	}
+
+}
+
+func TestInboxAgentBot(t *testing.T) {
+
+	var setInboxAgentBotRequests []string
+	agentBot := `{"agent_bot": {"id": 5, "name": "Shop Assistant", "outgoing_url": "https://bot.example.com"}}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		switch r.URL.Path {
+		case "/api/v1/accounts/1/inboxes/9/agent_bot":
+			w.Write([]byte(agentBot))
+		case "/api/v1/accounts/1/inboxes/9/set_agent_bot":
+			body, _ := io.ReadAll(r.Body)
+			setInboxAgentBotRequests = append(setInboxAgentBotRequests, string(body))
+			agentBot = `{"agent_bot": {}}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	bot, err := client.GetInboxAgentBot(1, 9, "agent-token")
+
+	if err != nil || bot == nil || bot.ID != 5 {
+		t.Fatalf("unexpected agent bot %+v: %v", bot, err)
+	}
+
+	if err := client.SetInboxAgentBot(1, 9, "agent-token", 6); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.ClearInboxAgentBot(1, 9, "agent-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(setInboxAgentBotRequests) != 2 || setInboxAgentBotRequests[0] != `{"agent_bot":6}` || setInboxAgentBotRequests[1] != `{"agent_bot":null}` {
+		t.Fatalf("unexpected requests: %v", setInboxAgentBotRequests)
+	}
+
+	bot, err = client.GetInboxAgentBot(1, 9, "agent-token")
+
+	if err != nil || bot != nil {
+		t.Fatalf("expected no agent bot, got %+v: %v", bot, err)
+	}
+
+}
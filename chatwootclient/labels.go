@@ -0,0 +1,257 @@
+package chatwootclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Label struct {
+	ID            int    `json:"id"`
+	Title         string `json:"title"`
+	Description   string `json:"description,omitempty"`
+	Color         string `json:"color,omitempty"`
+	ShowOnSidebar bool   `json:"show_on_sidebar"`
+}
+
+type CreateLabelRequest struct {
+	Title         string `json:"title"`
+	Description   string `json:"description,omitempty"`
+	Color         string `json:"color,omitempty"`
+	ShowOnSidebar bool   `json:"show_on_sidebar"`
+}
+
+// UpdateLabelRequest only changes the fields that are set.
+type UpdateLabelRequest struct {
+	Title         string  `json:"title,omitempty"`
+	Description   *string `json:"description,omitempty"`
+	Color         string  `json:"color,omitempty"`
+	ShowOnSidebar *bool   `json:"show_on_sidebar,omitempty"`
+}
+
+type ListLabelsResponse struct {
+	Payload []Label `json:"payload"`
+}
+
+func (client *ChatwootClient) ListLabels(accountId int64, agentToken string) ([]Label, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/labels", client.BaseUrl, accountId)
+
+	var listLabelsResponse ListLabelsResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &listLabelsResponse); err != nil {
+		return nil, err
+	}
+
+	return listLabelsResponse.Payload, nil
+}
+
+type ConversationLabelsResponse struct {
+	Payload []string `json:"payload"`
+}
+
+// ListConversationLabels returns the labels of the conversation.
+func (client *ChatwootClient) ListConversationLabels(accountId int64, conversationId int64, agentToken string) ([]string, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/labels", client.BaseUrl, accountId, conversationId)
+
+	var conversationLabelsResponse ConversationLabelsResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &conversationLabelsResponse); err != nil {
+		return nil, err
+	}
+
+	return conversationLabelsResponse.Payload, nil
+}
+
+func (client *ChatwootClient) CreateLabel(accountId int64, agentToken string, createLabelRequest CreateLabelRequest) (Label, error) {
+
+	if agentToken == "" {
+		return Label{}, errors.New("agentToken is empty. Creating labels requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/labels", client.BaseUrl, accountId)
+
+	var label Label
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, createLabelRequest, &label); err != nil {
+		return Label{}, err
+	}
+
+	client.invalidateLabelCatalog(accountId)
+
+	return label, nil
+}
+
+func (client *ChatwootClient) UpdateLabel(accountId int64, labelId int64, agentToken string, updateLabelRequest UpdateLabelRequest) (Label, error) {
+
+	if agentToken == "" {
+		return Label{}, errors.New("agentToken is empty. Updating labels requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/labels/%v", client.BaseUrl, accountId, labelId)
+
+	var label Label
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, updateLabelRequest, &label); err != nil {
+		return Label{}, err
+	}
+
+	client.invalidateLabelCatalog(accountId)
+
+	return label, nil
+}
+
+func (client *ChatwootClient) DeleteLabel(accountId int64, labelId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting labels requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/labels/%v", client.BaseUrl, accountId, labelId)
+
+	if err := client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil); err != nil {
+		return err
+	}
+
+	client.invalidateLabelCatalog(accountId)
+
+	return nil
+}
+
+// AddContactLabels sets the labels of the contact.
+func (client *ChatwootClient) AddContactLabels(accountId int64, contactId int64, agentToken string, labels []string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Adding labels requires a Chatwoot agent token")
+	}
+
+	if err := client.validateLabels(accountId, agentToken, labels); err != nil {
+		return err
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/contacts/%v/labels", client.BaseUrl, accountId, contactId)
+
+	return client.doJSONRequest(http.MethodPost, requestURL, agentToken, AddLabelsRequest{Labels: labels}, nil)
+}
+
+var ErrUnknownLabel = errors.New("unknown label")
+
+// LabelCatalog caches the labels of the accounts to validate labels before they are added in strict label mode. The
+// labels of an account are loaded on first use and reloaded after the ttl expired or when a label is unknown, at most
+// once per MinReloadInterval. A zero ttl never expires.
+type LabelCatalog struct {
+	ttl time.Duration
+
+	mutex    sync.Mutex
+	accounts map[int64]*reloadingCache[map[string]bool]
+}
+
+func NewLabelCatalog(ttl time.Duration) *LabelCatalog {
+	return &LabelCatalog{
+		ttl:      ttl,
+		accounts: map[int64]*reloadingCache[map[string]bool]{},
+	}
+}
+
+// Invalidate drops the cached labels of the account, they are reloaded on the next validation.
+func (labelCatalog *LabelCatalog) Invalidate(accountId int64) {
+
+	labelCatalog.mutex.Lock()
+	defer labelCatalog.mutex.Unlock()
+
+	delete(labelCatalog.accounts, accountId)
+}
+
+// Validate returns an error wrapping ErrUnknownLabel if one of the labels does not exist in the account. Labels are
+// compared case insensitive, as Chatwoot stores them in lower case.
+func (labelCatalog *LabelCatalog) Validate(client *ChatwootClient, accountId int64, agentToken string, labels []string) error {
+
+	cache := labelCatalog.account(accountId)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	titles, err := cache.get(labelCatalog.ttl, func() (map[string]bool, error) {
+		return loadLabelTitles(client, accountId, agentToken)
+	}, func(titles map[string]bool) bool {
+		return len(unknownLabels(titles, labels)) == 0
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if unknown := unknownLabels(titles, labels); len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownLabel, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// account returns the cache of the account, the catalog is only locked for the lookup, so that loading the labels of
+// one account does not block the other accounts.
+func (labelCatalog *LabelCatalog) account(accountId int64) *reloadingCache[map[string]bool] {
+
+	labelCatalog.mutex.Lock()
+	defer labelCatalog.mutex.Unlock()
+
+	cache, ok := labelCatalog.accounts[accountId]
+
+	if !ok {
+		cache = &reloadingCache[map[string]bool]{}
+		labelCatalog.accounts[accountId] = cache
+	}
+
+	return cache
+}
+
+// loadLabelTitles returns the lower case titles of the labels of the account.
+func loadLabelTitles(client *ChatwootClient, accountId int64, agentToken string) (map[string]bool, error) {
+
+	labels, err := client.ListLabels(accountId, agentToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string]bool, len(labels))
+
+	for _, label := range labels {
+		titles[strings.ToLower(label.Title)] = true
+	}
+
+	return titles, nil
+}
+
+func unknownLabels(titles map[string]bool, labels []string) []string {
+
+	var unknown []string
+
+	for _, label := range labels {
+		if !titles[strings.ToLower(label)] {
+			unknown = append(unknown, label)
+		}
+	}
+
+	return unknown
+}
+
+func (client *ChatwootClient) validateLabels(accountId int64, agentToken string, labels []string) error {
+
+	if client.LabelCatalog == nil {
+		return nil
+	}
+
+	return client.LabelCatalog.Validate(client, accountId, agentToken, labels)
+}
+
+func (client *ChatwootClient) invalidateLabelCatalog(accountId int64) {
+
+	if client.LabelCatalog != nil {
+		client.LabelCatalog.Invalidate(accountId)
+	}
+}
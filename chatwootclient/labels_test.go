@@ -0,0 +1,129 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStrictLabels(t *testing.T) {
+
+	listLabelsCalls := 0
+	var addedLabels []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		switch r.URL.Path {
+		case "/api/v1/accounts/1/labels":
+			listLabelsCalls++
+			w.Write([]byte(`{"payload": [{"id": 1, "title": "vip"}, {"id": 2, "title": "refund-request"}]}`))
+		case "/api/v1/accounts/1/conversations/7/labels", "/api/v1/accounts/1/contacts/8/labels":
+			var addLabelsRequest AddLabelsRequest
+			json.NewDecoder(r.Body).Decode(&addLabelsRequest)
+			addedLabels = append(addedLabels, addLabelsRequest.Labels...)
+			w.Write([]byte(`{"payload": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	// labels are not validated without catalog
+	if err := client.AddLabel(1, 7, "agent-token", "vpi"); err != nil {
+		t.Fatal(err)
+	}
+
+	client.LabelCatalog = NewLabelCatalog(0)
+
+	if err := client.AddLabels(1, 7, "agent-token", []string{"VIP", "refund-request"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AddContactLabels(1, 8, "agent-token", []string{"vip"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := client.AddLabel(1, 7, "agent-token", "vpi")
+
+	if !errors.Is(err, ErrUnknownLabel) {
+		t.Fatalf("expected ErrUnknownLabel, got %v", err)
+	}
+
+	if len(addedLabels) != 4 {
+		t.Fatalf("unexpected labels: %v", addedLabels)
+	}
+
+	// the catalog is loaded once, it is not reloaded for the unknown label within MinReloadInterval
+	if listLabelsCalls != 1 {
+		t.Fatalf("unexpected number of label list calls: %d", listLabelsCalls)
+	}
+
+	client.LabelCatalog.accounts[1].loadedAt = client.LabelCatalog.accounts[1].loadedAt.Add(-MinReloadInterval)
+
+	for i := 0; i < 2; i++ {
+		if err := client.AddLabel(1, 7, "agent-token", "vpi"); !errors.Is(err, ErrUnknownLabel) {
+			t.Fatalf("expected ErrUnknownLabel, got %v", err)
+		}
+	}
+
+	if listLabelsCalls != 2 {
+		t.Fatalf("expected one reload for the unknown label, got %d label list calls", listLabelsCalls)
+	}
+
+}
+
+func TestLabelCatalogLoadsAccountsIndependently(t *testing.T) {
+
+	loading := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path == "/api/v1/accounts/1/labels" {
+			close(loading)
+			<-release
+		}
+
+		w.Write([]byte(`{"payload": [{"id": 1, "title": "vip"}]}`))
+
+	}))
+
+	defer server.Close()
+	defer close(release)
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	labelCatalog := NewLabelCatalog(0)
+
+	go labelCatalog.Validate(&client, 1, "agent-token", []string{"vip"})
+
+	<-loading
+
+	validated := make(chan error)
+
+	go func() {
+		labelCatalog.Invalidate(3)
+		validated <- labelCatalog.Validate(&client, 2, "agent-token", []string{"vip"})
+	}()
+
+	select {
+	case err := <-validated:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the labels of account 2 were blocked by the load of account 1")
+	}
+
+}
@@ -0,0 +1,126 @@
+package chatwootclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	MacroVisibilityPersonal = "personal"
+	MacroVisibilityGlobal   = "global"
+)
+
+// Macro is a list of actions agents apply to conversations with a single click. The actions are the same as the
+// actions of automation rules, use the constructors like AssignTeamAction to build them.
+type Macro struct {
+	ID         int                `json:"id"`
+	AccountID  int                `json:"account_id,omitempty"`
+	Name       string             `json:"name"`
+	Visibility string             `json:"visibility"`
+	Actions    []AutomationAction `json:"actions"`
+	CreatedBy  *Agent             `json:"created_by,omitempty"`
+	UpdatedBy  *Agent             `json:"updated_by,omitempty"`
+}
+
+// MacroRequest creates or replaces a macro. Personal macros are only visible to the agent creating them.
+type MacroRequest struct {
+	Name       string             `json:"name"`
+	Visibility string             `json:"visibility,omitempty"`
+	Actions    []AutomationAction `json:"actions"`
+}
+
+type ListMacrosResponse struct {
+	Payload []Macro `json:"payload"`
+}
+
+type MacroResponse struct {
+	Payload Macro `json:"payload"`
+}
+
+type ExecuteMacroRequest struct {
+	ConversationIDs []int64 `json:"conversation_ids"`
+}
+
+// ListMacros returns the global macros of the account and the personal macros of the agent.
+func (client *ChatwootClient) ListMacros(accountId int64, agentToken string) ([]Macro, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/macros", client.BaseUrl, accountId)
+
+	var listMacrosResponse ListMacrosResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &listMacrosResponse); err != nil {
+		return nil, err
+	}
+
+	return listMacrosResponse.Payload, nil
+}
+
+func (client *ChatwootClient) GetMacro(accountId int64, macroId int64, agentToken string) (Macro, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/macros/%v", client.BaseUrl, accountId, macroId)
+
+	return client.doMacroRequest(http.MethodGet, requestURL, agentToken, nil)
+}
+
+func (client *ChatwootClient) CreateMacro(accountId int64, agentToken string, macroRequest MacroRequest) (Macro, error) {
+
+	if agentToken == "" {
+		return Macro{}, errors.New("agentToken is empty. Creating macros requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/macros", client.BaseUrl, accountId)
+
+	return client.doMacroRequest(http.MethodPost, requestURL, agentToken, macroRequest)
+}
+
+// UpdateMacro replaces the macro, actions that are not part of the request are removed.
+func (client *ChatwootClient) UpdateMacro(accountId int64, macroId int64, agentToken string, macroRequest MacroRequest) (Macro, error) {
+
+	if agentToken == "" {
+		return Macro{}, errors.New("agentToken is empty. Updating macros requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/macros/%v", client.BaseUrl, accountId, macroId)
+
+	return client.doMacroRequest(http.MethodPatch, requestURL, agentToken, macroRequest)
+}
+
+func (client *ChatwootClient) DeleteMacro(accountId int64, macroId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting macros requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/macros/%v", client.BaseUrl, accountId, macroId)
+
+	return client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil)
+}
+
+// ExecuteMacro applies the actions of the macro to the conversations. Chatwoot executes the macro asynchronously,
+// the actions may not have been applied yet when ExecuteMacro returns.
+func (client *ChatwootClient) ExecuteMacro(accountId int64, macroId int64, agentToken string, conversationIds []int64) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Executing macros requires a Chatwoot agent token")
+	}
+
+	if len(conversationIds) == 0 {
+		return nil
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/macros/%v/execute", client.BaseUrl, accountId, macroId)
+
+	return client.doJSONRequest(http.MethodPost, requestURL, agentToken, ExecuteMacroRequest{ConversationIDs: conversationIds}, nil)
+}
+
+func (client *ChatwootClient) doMacroRequest(method string, requestURL string, agentToken string, requestBody interface{}) (Macro, error) {
+
+	var macroResponse MacroResponse
+
+	if err := client.doJSONRequest(method, requestURL, agentToken, requestBody, &macroResponse); err != nil {
+		return Macro{}, err
+	}
+
+	return macroResponse.Payload, nil
+}
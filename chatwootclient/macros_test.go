@@ -0,0 +1,73 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMacros(t *testing.T) {
+
+	var macroRequest MacroRequest
+	var executeMacroRequest ExecuteMacroRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/accounts/1/macros":
+			w.Write([]byte(`{"payload": [{"id": 3, "name": "Escalate", "visibility": "global",
+				"actions": [{"action_name": "assign_team", "action_params": [2]}, {"action_name": "add_label", "action_params": ["escalated"]}],
+				"created_by": {"id": 5, "name": "Jane"}}]}`))
+		case "POST /api/v1/accounts/1/macros":
+			json.NewDecoder(r.Body).Decode(&macroRequest)
+			w.Write([]byte(`{"payload": {"id": 4, "name": "Close", "visibility": "personal"}}`))
+		case "POST /api/v1/accounts/1/macros/3/execute":
+			json.NewDecoder(r.Body).Decode(&executeMacroRequest)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	macros, err := client.ListMacros(1, "agent-token")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(macros) != 1 || macros[0].Visibility != MacroVisibilityGlobal || macros[0].CreatedBy.ID != 5 ||
+		macros[0].Actions[1].ActionName != ActionAddLabel || macros[0].Actions[1].ActionParams[0] != "escalated" {
+		t.Fatalf("unexpected macros: %+v", macros)
+	}
+
+	macro, err := client.CreateMacro(1, "agent-token", MacroRequest{
+		Name:       "Close",
+		Visibility: MacroVisibilityPersonal,
+		Actions:    []AutomationAction{SendMessageAction("Thanks!"), ResolveConversationAction()},
+	})
+
+	if err != nil || macro.ID != 4 {
+		t.Fatalf("unexpected macro %+v: %v", macro, err)
+	}
+
+	if len(macroRequest.Actions) != 2 || macroRequest.Actions[0].ActionParams[0] != "Thanks!" || macroRequest.Actions[1].ActionName != ActionResolveConversation {
+		t.Fatalf("unexpected request: %+v", macroRequest)
+	}
+
+	if err := client.ExecuteMacro(1, 3, "agent-token", []int64{7, 9}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(executeMacroRequest.ConversationIDs) != 2 || executeMacroRequest.ConversationIDs[1] != 9 {
+		t.Fatalf("unexpected execute request: %+v", executeMacroRequest)
+	}
+
+}
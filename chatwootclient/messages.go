@@ -0,0 +1,225 @@
+package chatwootclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MessageType is the type of a message. Chatwoot returns it as integer from the REST API and as string in webhook
+// and realtime events, both representations are decoded into the string constants.
+type MessageType string
+
+const (
+	MessageTypeIncoming MessageType = "incoming"
+	MessageTypeOutgoing MessageType = "outgoing"
+	MessageTypeActivity MessageType = "activity"
+	MessageTypeTemplate MessageType = "template"
+)
+
+var messageTypes = []MessageType{MessageTypeIncoming, MessageTypeOutgoing, MessageTypeActivity, MessageTypeTemplate}
+
+func (messageType *MessageType) UnmarshalJSON(data []byte) error {
+
+	var number int
+
+	if err := json.Unmarshal(data, &number); err == nil {
+		if number < 0 || number >= len(messageTypes) {
+			return fmt.Errorf("unknown message type %d", number)
+		}
+		*messageType = messageTypes[number]
+		return nil
+	}
+
+	var name string
+
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	*messageType = MessageType(name)
+
+	return nil
+}
+
+// Timestamp is a point in time that Chatwoot returns either as unix timestamp or as RFC 3339 string.
+type Timestamp struct {
+	time.Time
+}
+
+func (timestamp *Timestamp) UnmarshalJSON(data []byte) error {
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	var seconds float64
+
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		timestamp.Time = time.Unix(0, int64(seconds*float64(time.Second)))
+		return nil
+	}
+
+	var value string
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if value == "" {
+		return nil
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		timestamp.Time = time.Unix(seconds, 0)
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		// webhooks render timestamps in the Rails default format
+		parsed, err = time.Parse("2006-01-02 15:04:05 MST", value)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	timestamp.Time = parsed
+
+	return nil
+}
+
+func (timestamp Timestamp) MarshalJSON() ([]byte, error) {
+	if timestamp.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(timestamp.Unix())
+}
+
+type MessageSender struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email,omitempty"`
+	Type      string `json:"type,omitempty"` // contact, user or agent_bot
+	AvatarUrl string `json:"avatar_url,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+type MessageAttachment struct {
+	ID        int    `json:"id"`
+	MessageID int    `json:"message_id"`
+	FileType  string `json:"file_type"`
+	DataUrl   string `json:"data_url"`
+	ThumbUrl  string `json:"thumb_url,omitempty"`
+	FileSize  int64  `json:"file_size,omitempty"`
+}
+
+type Message struct {
+	ID                int                    `json:"id"`
+	Content           string                 `json:"content"`
+	ContentType       string                 `json:"content_type,omitempty"`
+	ContentAttributes map[string]interface{} `json:"content_attributes,omitempty"`
+	MessageType       MessageType            `json:"message_type"`
+	Private           bool                   `json:"private"`
+	Status            string                 `json:"status,omitempty"`
+	SourceID          string                 `json:"source_id,omitempty"`
+	AccountID         int                    `json:"account_id,omitempty"`
+	InboxID           int                    `json:"inbox_id,omitempty"`
+	ConversationID    int                    `json:"conversation_id,omitempty"`
+	SenderType        string                 `json:"sender_type,omitempty"`
+	SenderID          int                    `json:"sender_id,omitempty"`
+	Sender            *MessageSender         `json:"sender,omitempty"`
+	Attachments       []MessageAttachment    `json:"attachments,omitempty"`
+	CreatedAt         Timestamp              `json:"created_at"`
+}
+
+// Deleted reports whether the message has been deleted. Chatwoot keeps deleted messages as tombstones.
+func (message Message) Deleted() bool {
+	deleted, _ := message.ContentAttributes["deleted"].(bool)
+	return deleted
+}
+
+// UpdateMessageRequest changes the content and / or the content attributes of a message. Depending on the channel
+// Chatwoot only allows to update the content attributes, e.g. the submitted values of forms.
+type UpdateMessageRequest struct {
+	Content           string                 `json:"content,omitempty"`
+	ContentAttributes map[string]interface{} `json:"content_attributes,omitempty"`
+}
+
+func (client *ChatwootClient) UpdateMessage(accountId int64, conversationId int64, messageId int64, agentToken string, updateMessageRequest UpdateMessageRequest) (Message, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/messages/%v", client.BaseUrl, accountId, conversationId, messageId)
+
+	var message Message
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, updateMessageRequest, &message); err != nil {
+		return Message{}, err
+	}
+
+	return message, nil
+}
+
+// DeleteMessage deletes the message and returns its tombstone, i.e. the message with replaced content and the
+// deleted content attribute set.
+func (client *ChatwootClient) DeleteMessage(accountId int64, conversationId int64, messageId int64, agentToken string) (Message, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/messages/%v", client.BaseUrl, accountId, conversationId, messageId)
+
+	var message Message
+
+	if err := client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, &message); err != nil {
+		return Message{}, err
+	}
+
+	return message, nil
+}
+
+// ListMessagesRequest selects the messages returned by ListMessages. Chatwoot returns at most 20 messages, the latest
+// ones if neither Before nor After are set.
+type ListMessagesRequest struct {
+	Before int // only messages with a lower ID
+	After  int // only messages with a higher ID
+}
+
+type ListMessagesResponse struct {
+	Payload []Message `json:"payload"`
+}
+
+// ListMessages returns the messages of the conversation in ascending order. Unlike GetMessages it decodes the
+// messages into the Message model.
+func (client *ChatwootClient) ListMessages(accountId int64, conversationId int64, agentToken string, listMessagesRequest ListMessagesRequest) ([]Message, error) {
+	return client.ListMessagesWithContext(context.Background(), accountId, conversationId, agentToken, listMessagesRequest)
+}
+
+// ListMessagesWithContext is ListMessages with a context that cancels the request.
+func (client *ChatwootClient) ListMessagesWithContext(ctx context.Context, accountId int64, conversationId int64, agentToken string, listMessagesRequest ListMessagesRequest) ([]Message, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/conversations/%v/messages", client.BaseUrl, accountId, conversationId)
+
+	query := url.Values{}
+
+	if listMessagesRequest.Before > 0 {
+		query.Set("before", strconv.Itoa(listMessagesRequest.Before))
+	}
+
+	if listMessagesRequest.After > 0 {
+		query.Set("after", strconv.Itoa(listMessagesRequest.After))
+	}
+
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	var listMessagesResponse ListMessagesResponse
+
+	if err := client.doJSONRequestWithContext(ctx, http.MethodGet, requestURL, agentToken, nil, &listMessagesResponse); err != nil {
+		return nil, err
+	}
+
+	return listMessagesResponse.Payload, nil
+}
@@ -0,0 +1,75 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateAndDeleteMessage(t *testing.T) {
+
+	var updateMessageRequest UpdateMessageRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path != "/api/v1/accounts/1/conversations/2/messages/3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPatch:
+			json.NewDecoder(r.Body).Decode(&updateMessageRequest)
+			w.Write([]byte(`{"id": 3, "content": "corrected answer", "message_type": 1, "created_at": 1700000000}`))
+		case http.MethodDelete:
+			w.Write([]byte(`{"id": 3, "content": "This message was deleted", "message_type": 1, "content_attributes": {"deleted": true}, "created_at": 1700000000}`))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	message, err := client.UpdateMessage(1, 2, 3, "", UpdateMessageRequest{Content: "corrected answer"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updateMessageRequest.Content != "corrected answer" || message.MessageType != MessageTypeOutgoing || message.CreatedAt.Unix() != 1700000000 {
+		t.Fatalf("unexpected message: %+v", message)
+	}
+
+	message, err = client.DeleteMessage(1, 2, 3, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !message.Deleted() {
+		t.Fatalf("expected a deleted message: %+v", message)
+	}
+
+}
+
+func TestUnmarshalWebhookMessage(t *testing.T) {
+
+	var message Message
+
+	err := json.Unmarshal([]byte(`{"id": 3, "content": "hi", "message_type": "incoming", "created_at": "2023-05-25T10:00:00.000Z"}`), &message)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.MessageType != MessageTypeIncoming || message.CreatedAt.Year() != 2023 {
+		t.Fatalf("unexpected message: %+v", message)
+	}
+
+}
@@ -0,0 +1,54 @@
+package poller
+
+import "sync"
+
+// Checkpoint is the state of a conversation the poller has already emitted events for.
+type Checkpoint struct {
+	LastMessageID int    `json:"last_message_id"`
+	Status        string `json:"status"`
+}
+
+// CheckpointStore persists the checkpoints of the conversations. A persistent implementation allows the poller to
+// resume after a restart without emitting events twice or missing messages sent in the meantime.
+type CheckpointStore interface {
+	// Load returns the checkpoint of the conversation, found is false if there is none.
+	Load(accountId int64, conversationId int) (checkpoint Checkpoint, found bool, err error)
+	Save(accountId int64, conversationId int, checkpoint Checkpoint) error
+}
+
+type checkpointKey struct {
+	accountId      int64
+	conversationId int
+}
+
+// MemoryCheckpointStore is a CheckpointStore that keeps the checkpoints in memory.
+type MemoryCheckpointStore struct {
+	mutex       sync.Mutex
+	checkpoints map[checkpointKey]Checkpoint
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: map[checkpointKey]Checkpoint{},
+	}
+}
+
+func (store *MemoryCheckpointStore) Load(accountId int64, conversationId int) (Checkpoint, bool, error) {
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	checkpoint, found := store.checkpoints[checkpointKey{accountId, conversationId}]
+
+	return checkpoint, found, nil
+}
+
+func (store *MemoryCheckpointStore) Save(accountId int64, conversationId int, checkpoint Checkpoint) error {
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.checkpoints[checkpointKey{accountId, conversationId}] = checkpoint
+
+	return nil
+}
@@ -0,0 +1,263 @@
+// Package poller is an event source for environments that can neither receive webhooks nor keep a websocket open. It
+// periodically lists the conversations of an account and emits synthetic message_created and
+// conversation_status_changed events to a webhook.Handler.
+package poller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient/webhook"
+)
+
+const (
+	DefaultInterval = 30 * time.Second
+	DefaultMaxPages = 10
+)
+
+// Poller emits the events of the conversations that had activity since the previous poll. The first poll only
+// records checkpoints for conversations without one, so the existing history is not emitted. Conversations that
+// appear later are new and all their messages are emitted.
+//
+// The events are dispatched using Handler.Dispatch, also if the handler has a Queue, as the conversations are polled
+// one after another anyway. The checkpoint of a conversation only advances after the handler funcs of its event
+// succeeded, events whose handler funcs fail are removed from the DedupeStore of the handler and emitted again by the
+// next poll. Errors that do not stop Run are passed to ErrorHandler.
+type Poller struct {
+	Client      *chatwootclient.ChatwootClient
+	AccountID   int64
+	AgentToken  string
+	Handler     *webhook.Handler
+	Checkpoints CheckpointStore
+
+	InboxID      int64 // optionally restricts the poller to an inbox
+	Interval     time.Duration
+	MaxPages     int // pages of 25 conversations listed per poll at most
+	ErrorHandler func(err error)
+
+	lastPoll time.Time
+}
+
+func New(client *chatwootclient.ChatwootClient, accountId int64, agentToken string, handler *webhook.Handler) *Poller {
+	return &Poller{
+		Client:      client,
+		AccountID:   accountId,
+		AgentToken:  agentToken,
+		Handler:     handler,
+		Checkpoints: NewMemoryCheckpointStore(),
+	}
+}
+
+// Run polls immediately and then every Interval until the context is done, it returns the error of the context. A
+// poll in progress is aborted when the context is done.
+func (poller *Poller) Run(ctx context.Context) error {
+
+	ticker := time.NewTicker(poller.interval())
+	defer ticker.Stop()
+
+	for {
+		if err := poller.Poll(ctx); err != nil && ctx.Err() == nil && poller.ErrorHandler != nil {
+			poller.ErrorHandler(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll emits the events since the previous poll. Conversations are processed even if others fail, the first error
+// is returned. When the context is done, Poll stops before the next request or event and returns the error of the
+// context, the events emitted so far are not emitted again. Poll must not be called concurrently.
+func (poller *Poller) Poll(ctx context.Context) error {
+
+	startedAt := time.Now()
+	initial := poller.lastPoll.IsZero()
+
+	// conversations are ordered by last activity, older ones were handled by previous polls, the margin of an interval
+	// compensates for clock differences between Chatwoot and the poller
+	since := poller.lastPoll.Add(-poller.interval())
+
+	var firstErr error
+
+	for page := 1; page <= poller.maxPages(); page++ {
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		conversations, err := poller.Client.ListConversationsWithContext(ctx, poller.AccountID, poller.AgentToken, chatwootclient.ListConversationsRequest{
+			Status:  "all",
+			InboxID: poller.InboxID,
+			Page:    page,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, conversation := range conversations {
+			if err := poller.pollConversation(ctx, conversation, initial); err != nil && firstErr == nil {
+				firstErr = err
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		if len(conversations) == 0 || (!initial && conversations[len(conversations)-1].LastActivityAt.Before(since)) {
+			break
+		}
+	}
+
+	if firstErr == nil {
+		poller.lastPoll = startedAt
+	}
+
+	return firstErr
+}
+
+func (poller *Poller) pollConversation(ctx context.Context, conversation chatwootclient.Conversation, initial bool) error {
+
+	checkpoint, found, err := poller.Checkpoints.Load(poller.AccountID, conversation.ID)
+
+	if err != nil {
+		return err
+	}
+
+	if !found && initial {
+		checkpoint.Status = conversation.Status
+		if len(conversation.Messages) > 0 {
+			checkpoint.LastMessageID = conversation.Messages[len(conversation.Messages)-1].ID
+		}
+		return poller.Checkpoints.Save(poller.AccountID, conversation.ID, checkpoint)
+	}
+
+	// the list contains the last message of the conversation, messages only have to be fetched if it is new
+	if len(conversation.Messages) == 0 || conversation.Messages[len(conversation.Messages)-1].ID > checkpoint.LastMessageID {
+
+		messages, err := poller.messagesAfter(ctx, conversation.ID, checkpoint.LastMessageID)
+
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			event := &webhook.MessageEvent{
+				Message:      message,
+				Event:        chatwootclient.EventMessageCreated,
+				Account:      webhook.Account{ID: int(poller.AccountID)},
+				Inbox:        chatwootclient.Inbox{ID: conversation.InboxID},
+				Conversation: conversation,
+			}
+
+			if err := poller.Handler.Dispatch(event); err != nil {
+				return err
+			}
+
+			checkpoint.LastMessageID = message.ID
+
+			if err := poller.Checkpoints.Save(poller.AccountID, conversation.ID, checkpoint); err != nil {
+				return err
+			}
+		}
+	}
+
+	if checkpoint.Status == conversation.Status {
+		return nil
+	}
+
+	if checkpoint.Status != "" {
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		event := &webhook.ConversationEvent{
+			Conversation: conversation,
+			Event:        chatwootclient.EventConversationStatusChanged,
+			Account:      webhook.Account{ID: int(poller.AccountID)},
+			ChangedAttributes: webhook.ChangedAttributes{
+				{"status": {PreviousValue: checkpoint.Status, CurrentValue: conversation.Status}},
+			},
+		}
+
+		if err := poller.Handler.Dispatch(event); err != nil {
+			return err
+		}
+	}
+
+	checkpoint.Status = conversation.Status
+
+	return poller.Checkpoints.Save(poller.AccountID, conversation.ID, checkpoint)
+}
+
+// messagesAfter returns the messages with an ID above the given one in ascending order. Chatwoot returns the messages
+// in pages of 20, the pages are fetched from the latest backwards until the given ID is reached.
+func (poller *Poller) messagesAfter(ctx context.Context, conversationId int, afterId int) ([]chatwootclient.Message, error) {
+
+	var messages []chatwootclient.Message
+
+	before := 0
+
+	for {
+		page, err := poller.Client.ListMessagesWithContext(ctx, poller.AccountID, int64(conversationId), poller.AgentToken, chatwootclient.ListMessagesRequest{
+			Before: before,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		reachedAfterId := false
+
+		for _, message := range page {
+			if message.ID > afterId {
+				messages = append(messages, message)
+			} else {
+				reachedAfterId = true
+			}
+		}
+
+		if len(page) == 0 || reachedAfterId {
+			break
+		}
+
+		before = page[0].ID
+		for _, message := range page {
+			if message.ID < before {
+				before = message.ID
+			}
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+
+	return messages, nil
+}
+
+func (poller *Poller) interval() time.Duration {
+	if poller.Interval > 0 {
+		return poller.Interval
+	}
+	return DefaultInterval
+}
+
+func (poller *Poller) maxPages() int {
+	if poller.MaxPages > 0 {
+		return poller.MaxPages
+	}
+	return DefaultMaxPages
+}
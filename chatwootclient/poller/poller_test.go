@@ -0,0 +1,232 @@
+package poller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient/webhook"
+)
+
+// fakeChatwoot serves the conversations and their messages, the messages are returned in pages of 2.
+type fakeChatwoot struct {
+	mutex         sync.Mutex
+	conversations []chatwootclient.Conversation
+	messages      map[int][]chatwootclient.Message
+}
+
+func (chatwoot *fakeChatwoot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	chatwoot.mutex.Lock()
+	defer chatwoot.mutex.Unlock()
+
+	if r.URL.Path == "/api/v1/accounts/1/conversations" {
+		if r.URL.Query().Get("page") != "1" {
+			w.Write([]byte(`{"data": {"payload": []}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"payload": chatwoot.conversations}})
+		return
+	}
+
+	var conversationId int
+
+	if _, err := fmt.Sscanf(r.URL.Path, "/api/v1/accounts/1/conversations/%d/messages", &conversationId); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	before, _ := strconv.Atoi(r.URL.Query().Get("before"))
+
+	var page []chatwootclient.Message
+
+	for _, message := range chatwoot.messages[conversationId] {
+		if before == 0 || message.ID < before {
+			page = append(page, message)
+		}
+	}
+
+	if len(page) > 2 {
+		page = page[len(page)-2:]
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"payload": page})
+}
+
+func (chatwoot *fakeChatwoot) addMessage(conversationId int, inboxId int, messageId int, status string) {
+
+	chatwoot.mutex.Lock()
+	defer chatwoot.mutex.Unlock()
+
+	message := chatwootclient.Message{ID: messageId, Content: fmt.Sprint("message ", messageId), MessageType: chatwootclient.MessageTypeIncoming}
+	chatwoot.messages[conversationId] = append(chatwoot.messages[conversationId], message)
+
+	conversation := chatwootclient.Conversation{
+		ID:             conversationId,
+		InboxID:        inboxId,
+		Status:         status,
+		Messages:       []chatwootclient.Message{message},
+		LastActivityAt: chatwootclient.Timestamp{Time: time.Now()},
+	}
+
+	for i, existing := range chatwoot.conversations {
+		if existing.ID == conversationId {
+			chatwoot.conversations = append(chatwoot.conversations[:i], chatwoot.conversations[i+1:]...)
+			break
+		}
+	}
+
+	chatwoot.conversations = append([]chatwootclient.Conversation{conversation}, chatwoot.conversations...)
+}
+
+func TestPoller(t *testing.T) {
+
+	chatwoot := &fakeChatwoot{messages: map[int][]chatwootclient.Message{}}
+	chatwoot.addMessage(9, 3, 41, chatwootclient.ConversationStatusPending)
+	chatwoot.addMessage(9, 3, 42, chatwootclient.ConversationStatusPending)
+
+	server := httptest.NewServer(chatwoot)
+	defer server.Close()
+
+	var events []string
+	failMessage := 45
+
+	// failed events are emitted again, the queue is bypassed and the dedupe key of the failed event is removed
+	handler := webhook.NewHandler()
+	handler.DedupeStore = webhook.NewMemoryReplayCache()
+	handler.Queue = webhook.NewQueue(handler)
+
+	handler.OnMessageCreated(func(event *webhook.MessageEvent) error {
+		if event.ID == failMessage {
+			failMessage = 0
+			return errors.New("failed")
+		}
+		events = append(events, fmt.Sprintf("message %d/%d", event.Conversation.ID, event.ID))
+		return nil
+	})
+
+	handler.OnConversationStatusChanged(func(event *webhook.ConversationEvent) error {
+		change, _ := event.ChangedAttributes.Get("status")
+		events = append(events, fmt.Sprintf("status %d %v->%v", event.ID, change.PreviousValue, change.CurrentValue))
+		return nil
+	})
+
+	client := chatwootclient.NewChatwootClient(server.URL)
+	poller := New(&client, 1, "token", handler)
+
+	// the first poll records the existing history without emitting it
+	if err := poller.Poll(context.Background()); err != nil || len(events) != 0 {
+		t.Fatalf("unexpected first poll: %v %v", err, events)
+	}
+
+	chatwoot.addMessage(9, 3, 43, chatwootclient.ConversationStatusPending)
+	chatwoot.addMessage(9, 3, 44, chatwootclient.ConversationStatusPending)
+	chatwoot.addMessage(9, 3, 45, chatwootclient.ConversationStatusPending)
+	chatwoot.addMessage(9, 3, 46, chatwootclient.ConversationStatusOpen)
+	chatwoot.addMessage(10, 3, 50, chatwootclient.ConversationStatusPending)
+
+	if err := poller.Poll(context.Background()); err == nil {
+		t.Fatal("expected the handler error to be returned")
+	}
+
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "message 10/50, message 9/43, message 9/44, message 9/45, message 9/46, status 9 pending->open"
+
+	if strings.Join(events, ", ") != expected {
+		t.Fatalf("unexpected events: %v", events)
+	}
+
+	if err := poller.Poll(context.Background()); err != nil || len(events) != 6 {
+		t.Fatalf("expected no new events: %v %v", err, events)
+	}
+
+	checkpoint, _, _ := poller.Checkpoints.Load(1, 9)
+
+	if checkpoint.LastMessageID != 46 || checkpoint.Status != chatwootclient.ConversationStatusOpen {
+		t.Fatalf("unexpected checkpoint: %+v", checkpoint)
+	}
+
+}
+
+func TestRunStopsPollInProgress(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		if r.URL.Path != "/api/v1/accounts/1/conversations" {
+			if r.URL.Query().Get("before") != "" {
+				w.Write([]byte(`{"payload": []}`))
+				return
+			}
+			w.Write([]byte(`{"payload": [{"id": 1, "content": "hi", "message_type": 0}]}`))
+			return
+		}
+
+		if page > 1 {
+			// the poll is cancelled while it waits for the second page
+			cancel()
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+
+		fmt.Fprintf(w, `{"data": {"payload": [{"id": %d, "status": "open", "last_activity_at": %d}]}}`, page, time.Now().Unix())
+
+	}))
+
+	defer server.Close()
+
+	var events []int
+
+	handler := webhook.NewHandler()
+	handler.OnMessageCreated(func(event *webhook.MessageEvent) error {
+		events = append(events, event.Conversation.ID)
+		return nil
+	})
+
+	client := chatwootclient.NewChatwootClient(server.URL)
+	poller := New(&client, 1, "token", handler)
+	poller.MaxPages = 100
+	poller.lastPoll = time.Now()
+	poller.ErrorHandler = func(err error) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	stopped := make(chan error)
+
+	go func() {
+		stopped <- poller.Run(ctx)
+	}()
+
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not stop the poll in progress")
+	}
+
+	if len(events) != 1 || events[0] != 1 {
+		t.Fatalf("unexpected events: %v", events)
+	}
+
+}
@@ -0,0 +1,328 @@
+// Package realtime receives Chatwoot events over the ActionCable websocket used by the Chatwoot dashboard. It is an
+// alternative to webhooks for deployments that cannot receive callbacks, the events are decoded into the typed events
+// of the webhook package and delivered to a webhook.Handler.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient/webhook"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	DefaultMinBackoff   = time.Second
+	DefaultMaxBackoff   = time.Minute
+	DefaultStaleTimeout = 10 * time.Second
+)
+
+// maxMessageSize limits the size of a received message, events of conversations with many messages can be large.
+const maxMessageSize = 16 << 20
+
+const handshakeTimeout = 10 * time.Second
+
+var (
+	ErrSubscriptionRejected = errors.New("subscription to RoomChannel rejected")
+	ErrDisconnected         = errors.New("disconnected by the server")
+)
+
+// Client subscribes to the RoomChannel of a user and delivers the received events to Handler. The pubsub token is
+// returned with the profile of the user, AccountID and UserID identify the user in the channel.
+//
+// The connection is reestablished with an exponential backoff between MinBackoff and MaxBackoff when it is lost or no
+// message (ActionCable pings every 3 seconds) is received for StaleTimeout. Errors that do not stop the client, like
+// errors of the handler funcs, are passed to ErrorHandler.
+type Client struct {
+	CableUrl    string
+	PubsubToken string
+	AccountID   int64
+	UserID      int64
+	Handler     *webhook.Handler
+
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	StaleTimeout time.Duration
+	ErrorHandler func(err error)
+}
+
+// New returns a client for the /cable endpoint of the Chatwoot installation of the ChatwootClient.
+func New(client *chatwootclient.ChatwootClient, pubsubToken string, accountId int64, userId int64, handler *webhook.Handler) *Client {
+	return &Client{
+		CableUrl:    CableUrl(client.BaseUrl),
+		PubsubToken: pubsubToken,
+		AccountID:   accountId,
+		UserID:      userId,
+		Handler:     handler,
+	}
+}
+
+// CableUrl returns the websocket url of the ActionCable endpoint for the base url of a Chatwoot installation.
+func CableUrl(baseUrl string) string {
+
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+
+	switch {
+	case strings.HasPrefix(baseUrl, "https://"):
+		baseUrl = "wss://" + strings.TrimPrefix(baseUrl, "https://")
+	case strings.HasPrefix(baseUrl, "http://"):
+		baseUrl = "ws://" + strings.TrimPrefix(baseUrl, "http://")
+	}
+
+	return baseUrl + "/cable"
+}
+
+// cableMessage is a message sent by ActionCable. Pings carry the time as message, broadcasts the event.
+type cableMessage struct {
+	Type       string          `json:"type,omitempty"`
+	Identifier string          `json:"identifier,omitempty"`
+	Message    json.RawMessage `json:"message,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+	Reconnect  *bool           `json:"reconnect,omitempty"`
+}
+
+type cableCommand struct {
+	Command    string `json:"command"`
+	Identifier string `json:"identifier"`
+}
+
+// Run connects and delivers events until the context is done or the server refuses the subscription. It returns the
+// error of the context or ErrSubscriptionRejected or ErrDisconnected.
+func (client *Client) Run(ctx context.Context) error {
+
+	backoff := client.minBackoff()
+
+	for {
+		subscribed, err := client.connect(ctx)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if errors.Is(err, ErrSubscriptionRejected) || errors.Is(err, ErrDisconnected) {
+			return err
+		}
+
+		client.reportError(err)
+
+		if subscribed {
+			backoff = client.minBackoff()
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if maxBackoff := client.maxBackoff(); backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// connect handles a single connection until it is lost, it reports whether the subscription was confirmed.
+func (client *Client) connect(ctx context.Context) (bool, error) {
+
+	header := http.Header{}
+	header.Set("Origin", originOf(client.CableUrl))
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: handshakeTimeout,
+	}
+
+	conn, response, err := dialer.DialContext(ctx, client.CableUrl, header)
+
+	if err != nil {
+		if response != nil {
+			return false, fmt.Errorf("websocket handshake failed: %s", response.Status)
+		}
+		return false, err
+	}
+
+	defer conn.Close()
+
+	conn.SetReadLimit(maxMessageSize)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// closing the connection unblocks the read when the context is done
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	identifier, err := client.identifier()
+
+	if err != nil {
+		return false, err
+	}
+
+	subscribed := false
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(client.staleTimeout()))
+
+		_, data, err := conn.ReadMessage()
+
+		if err != nil {
+			return subscribed, err
+		}
+
+		var message cableMessage
+
+		if err := json.Unmarshal(data, &message); err != nil {
+			return subscribed, fmt.Errorf("invalid ActionCable message: %w", err)
+		}
+
+		switch message.Type {
+		case "welcome":
+			command, _ := json.Marshal(cableCommand{Command: "subscribe", Identifier: identifier})
+			if err := conn.WriteMessage(websocket.TextMessage, command); err != nil {
+				return subscribed, err
+			}
+		case "confirm_subscription":
+			subscribed = true
+		case "reject_subscription":
+			return subscribed, ErrSubscriptionRejected
+		case "disconnect":
+			if message.Reconnect != nil && !*message.Reconnect {
+				return subscribed, fmt.Errorf("%w: %s", ErrDisconnected, message.Reason)
+			}
+			return subscribed, fmt.Errorf("disconnected by the server: %s", message.Reason)
+		case "":
+			if message.Identifier == identifier && len(message.Message) > 0 {
+				client.deliver(message.Message)
+			}
+		}
+	}
+}
+
+func (client *Client) deliver(payload json.RawMessage) {
+
+	event, err := DecodeEvent(payload)
+
+	if err != nil {
+		client.reportError(err)
+		return
+	}
+
+	if err := client.Handler.Deliver(event); err != nil {
+		client.reportError(err)
+	}
+}
+
+// DecodeEvent decodes a RoomChannel broadcast like {"event": "message.created", "data": {...}} into the typed event
+// of the webhook package. The dotted event names are mapped to the webhook event names.
+func DecodeEvent(payload []byte) (webhook.Event, error) {
+
+	var envelope struct {
+		Event string          `json:"event"`
+		Data  json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, err
+	}
+
+	if envelope.Event == "" {
+		return nil, webhook.ErrMissingEvent
+	}
+
+	eventName := chatwootclient.WebhookEvent(strings.ReplaceAll(envelope.Event, ".", "_"))
+
+	event, err := webhook.DecodeEvent(eventName, envelope.Data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the data only carries the account id, webhooks send the account as object
+	var data struct {
+		AccountID int `json:"account_id"`
+	}
+
+	json.Unmarshal(envelope.Data, &data)
+
+	switch event := event.(type) {
+	case *webhook.MessageEvent:
+		event.Account.ID = data.AccountID
+	case *webhook.ConversationEvent:
+		event.Account.ID = data.AccountID
+	case *webhook.ContactEvent:
+		event.Account.ID = data.AccountID
+	case *webhook.TypingEvent:
+		event.Account.ID = data.AccountID
+	}
+
+	return event, nil
+}
+
+func (client *Client) identifier() (string, error) {
+
+	identifier, err := json.Marshal(struct {
+		Channel     string `json:"channel"`
+		PubsubToken string `json:"pubsub_token"`
+		AccountID   int64  `json:"account_id"`
+		UserID      int64  `json:"user_id"`
+	}{"RoomChannel", client.PubsubToken, client.AccountID, client.UserID})
+
+	return string(identifier), err
+}
+
+func (client *Client) reportError(err error) {
+	if err != nil && client.ErrorHandler != nil {
+		client.ErrorHandler(err)
+	}
+}
+
+func (client *Client) minBackoff() time.Duration {
+	if client.MinBackoff > 0 {
+		return client.MinBackoff
+	}
+	return DefaultMinBackoff
+}
+
+func (client *Client) maxBackoff() time.Duration {
+	if client.MaxBackoff > 0 {
+		return client.MaxBackoff
+	}
+	return DefaultMaxBackoff
+}
+
+func (client *Client) staleTimeout() time.Duration {
+	if client.StaleTimeout > 0 {
+		return client.StaleTimeout
+	}
+	return DefaultStaleTimeout
+}
+
+// originOf returns the http origin of the websocket url, ActionCable rejects connections from unknown origins.
+func originOf(cableUrl string) string {
+
+	origin := strings.TrimSuffix(cableUrl, "/cable")
+
+	switch {
+	case strings.HasPrefix(origin, "wss://"):
+		return "https://" + strings.TrimPrefix(origin, "wss://")
+	case strings.HasPrefix(origin, "ws://"):
+		return "http://" + strings.TrimPrefix(origin, "ws://")
+	}
+
+	return origin
+}
@@ -0,0 +1,241 @@
+package realtime
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient/webhook"
+	"github.com/gorilla/websocket"
+)
+
+// fakeCable is an ActionCable server, each connection is handled by the next script.
+type fakeCable struct {
+	t       *testing.T
+	mutex   sync.Mutex
+	scripts []func(conn *websocket.Conn, identifier string)
+}
+
+func (cable *fakeCable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.URL.Path != "/cable" || r.Header.Get("Origin") == "" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	cable.mutex.Lock()
+	if len(cable.scripts) == 0 {
+		cable.mutex.Unlock()
+		http.Error(w, "no more connections expected", http.StatusServiceUnavailable)
+		return
+	}
+	script := cable.scripts[0]
+	cable.scripts = cable.scripts[1:]
+	cable.mutex.Unlock()
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+
+	if err != nil {
+		cable.t.Error(err)
+		return
+	}
+
+	defer conn.Close()
+
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"welcome"}`))
+
+	_, data, err := conn.ReadMessage()
+
+	if err != nil {
+		cable.t.Error(err)
+		return
+	}
+
+	var command cableCommand
+
+	if err := json.Unmarshal(data, &command); err != nil || command.Command != "subscribe" {
+		cable.t.Errorf("unexpected command %s", data)
+		return
+	}
+
+	script(conn, command.Identifier)
+}
+
+func broadcast(conn *websocket.Conn, identifier string, event string, data string) {
+	message, _ := json.Marshal(cableMessage{Identifier: identifier, Message: json.RawMessage(fmt.Sprintf(`{"event":%q,"data":%s}`, event, data))})
+	conn.WriteMessage(websocket.TextMessage, message)
+}
+
+func confirm(conn *websocket.Conn, identifier string) {
+	message, _ := json.Marshal(cableMessage{Type: "confirm_subscription", Identifier: identifier})
+	conn.WriteMessage(websocket.TextMessage, message)
+}
+
+func TestClient(t *testing.T) {
+
+	content := strings.Repeat("a", 70000)
+
+	cable := &fakeCable{t: t}
+	closed := make(chan struct{})
+
+	cable.scripts = append(cable.scripts,
+		func(conn *websocket.Conn, identifier string) {
+			if !strings.Contains(identifier, `"channel":"RoomChannel","pubsub_token":"token","account_id":1,"user_id":2`) {
+				t.Errorf("unexpected identifier %s", identifier)
+			}
+			confirm(conn, identifier)
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"ping","message":1709287200}`))
+			broadcast(conn, identifier, "message.created", fmt.Sprintf(`{"id":42,"content":%q,"message_type":0,"account_id":1,"conversation_id":9,"conversation":{"id":9}}`, content))
+			// the connection is dropped, the client reconnects
+		},
+		func(conn *websocket.Conn, identifier string) {
+			confirm(conn, identifier)
+			broadcast(conn, identifier, "conversation.status_changed", `{"id":9,"status":"resolved","account_id":1}`)
+			conn.ReadMessage()
+			close(closed)
+		},
+	)
+
+	server := httptest.NewServer(cable)
+	defer server.Close()
+
+	handler := webhook.NewHandler()
+	events := make(chan webhook.Event, 2)
+
+	handler.OnMessageCreated(func(event *webhook.MessageEvent) error {
+		events <- event
+		return nil
+	})
+
+	handler.OnConversationStatusChanged(func(event *webhook.ConversationEvent) error {
+		events <- event
+		return nil
+	})
+
+	chatwootClient := chatwootclient.NewChatwootClient(server.URL)
+	client := New(&chatwootClient, "token", 1, 2, handler)
+	client.MinBackoff = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+
+	go func() {
+		result <- client.Run(ctx)
+	}()
+
+	messageEvent := (<-events).(*webhook.MessageEvent)
+
+	if messageEvent.ID != 42 || messageEvent.Content != content || messageEvent.MessageType != chatwootclient.MessageTypeIncoming ||
+		messageEvent.Account.ID != 1 || messageEvent.Conversation.ID != 9 {
+		t.Fatalf("unexpected message event: %+v", messageEvent.Message)
+	}
+
+	conversationEvent := (<-events).(*webhook.ConversationEvent)
+
+	if conversationEvent.ID != 9 || conversationEvent.Status != chatwootclient.ConversationStatusResolved {
+		t.Fatalf("unexpected conversation event: %+v", conversationEvent)
+	}
+
+	cancel()
+
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	<-closed
+
+}
+
+func TestClientRejected(t *testing.T) {
+
+	cable := &fakeCable{t: t}
+	cable.scripts = append(cable.scripts, func(conn *websocket.Conn, identifier string) {
+		message, _ := json.Marshal(cableMessage{Type: "reject_subscription", Identifier: identifier})
+		conn.WriteMessage(websocket.TextMessage, message)
+		conn.ReadMessage()
+	})
+
+	server := httptest.NewServer(cable)
+	defer server.Close()
+
+	chatwootClient := chatwootclient.NewChatwootClient(server.URL)
+	client := New(&chatwootClient, "invalid", 1, 2, webhook.NewHandler())
+
+	if err := client.Run(context.Background()); err != ErrSubscriptionRejected {
+		t.Fatalf("expected ErrSubscriptionRejected, got %v", err)
+	}
+
+}
+
+func TestCableUrl(t *testing.T) {
+
+	if cableUrl := CableUrl("https://app.chatwoot.com/"); cableUrl != "wss://app.chatwoot.com/cable" {
+		t.Fatalf("unexpected url %s", cableUrl)
+	}
+
+	if origin := originOf(CableUrl("http://localhost:3000")); origin != "http://localhost:3000" {
+		t.Fatalf("unexpected origin %s", origin)
+	}
+
+}
+
+func TestClientInvalidHandshake(t *testing.T) {
+
+	responses := map[string]string{
+		"missing Upgrade":    "Connection: Upgrade\r\n",
+		"missing Connection": "Upgrade: websocket\r\n",
+		"wrong Upgrade":      "Upgrade: h2c\r\nConnection: Upgrade\r\n",
+	}
+
+	for name, headers := range responses {
+		t.Run(name, func(t *testing.T) {
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+				conn, buffer, err := w.(http.Hijacker).Hijack()
+
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				defer conn.Close()
+
+				hash := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+
+				fmt.Fprintf(buffer, "HTTP/1.1 101 Switching Protocols\r\n%sSec-WebSocket-Accept: %s\r\n\r\n{\"type\":\"welcome\"}",
+					headers, base64.StdEncoding.EncodeToString(hash[:]))
+				buffer.Flush()
+
+			}))
+
+			defer server.Close()
+
+			chatwootClient := chatwootclient.NewChatwootClient(server.URL)
+			client := New(&chatwootClient, "token", 1, 2, webhook.NewHandler())
+
+			if subscribed, err := client.connect(context.Background()); subscribed || err == nil ||
+				!strings.Contains(err.Error(), "websocket handshake failed") {
+				t.Fatalf("expected the handshake to fail, got %v", err)
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,54 @@
+package chatwootclient
+
+import (
+	"sync"
+	"time"
+)
+
+// MinReloadInterval limits how often TeamCache, LabelCatalog and CustomAttributeCatalog reload from Chatwoot because of
+// an unknown name, so that lookups of names that do not exist do not cause a request every time.
+const MinReloadInterval = time.Minute
+
+// reloadingCache holds a value loaded from Chatwoot, e.g. the teams of an account. The value is loaded on first use
+// and reloaded after the ttl expired, a zero ttl never expires. When the value misses an entry, it might have been
+// created after the value was loaded, so it is reloaded if it is older than MinReloadInterval. The owner of the cache
+// has to hold mutex while calling get or reload, caches of different accounts are locked independently, so that a slow
+// load of one account does not block the others.
+type reloadingCache[T any] struct {
+	mutex    sync.Mutex
+	value    T
+	loaded   bool
+	loadedAt time.Time
+}
+
+// get returns the cached value, loading it if it expired or if hit reports a miss and the value may be reloaded.
+func (cache *reloadingCache[T]) get(ttl time.Duration, load func() (T, error), hit func(value T) bool) (T, error) {
+
+	age := time.Since(cache.loadedAt)
+
+	if !cache.loaded || (ttl > 0 && age > ttl) {
+		return cache.reload(load)
+	}
+
+	if !hit(cache.value) && age >= MinReloadInterval {
+		return cache.reload(load)
+	}
+
+	return cache.value, nil
+}
+
+// reload loads the value, the cached value is kept if loading fails.
+func (cache *reloadingCache[T]) reload(load func() (T, error)) (T, error) {
+
+	value, err := load()
+
+	if err != nil {
+		return value, err
+	}
+
+	cache.value = value
+	cache.loaded = true
+	cache.loadedAt = time.Now()
+
+	return value, nil
+}
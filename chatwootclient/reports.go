@@ -0,0 +1,228 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type ReportMetric string
+
+const (
+	ReportMetricConversationsCount    ReportMetric = "conversations_count"
+	ReportMetricIncomingMessagesCount ReportMetric = "incoming_messages_count"
+	ReportMetricOutgoingMessagesCount ReportMetric = "outgoing_messages_count"
+	ReportMetricAvgFirstResponseTime  ReportMetric = "avg_first_response_time"
+	ReportMetricAvgResolutionTime     ReportMetric = "avg_resolution_time"
+	ReportMetricResolutionsCount      ReportMetric = "resolutions_count"
+	ReportMetricReplyTime             ReportMetric = "reply_time"
+	ReportMetricBotResolutionsCount   ReportMetric = "bot_resolutions_count"
+	ReportMetricBotHandoffsCount      ReportMetric = "bot_handoffs_count"
+)
+
+// ReportType is the scope of a report, all types but ReportTypeAccount require the ID of the agent, inbox, label
+// or team.
+type ReportType string
+
+const (
+	ReportTypeAccount ReportType = "account"
+	ReportTypeAgent   ReportType = "agent"
+	ReportTypeInbox   ReportType = "inbox"
+	ReportTypeLabel   ReportType = "label"
+	ReportTypeTeam    ReportType = "team"
+)
+
+type ReportGroupBy string
+
+const (
+	ReportGroupByDay   ReportGroupBy = "day"
+	ReportGroupByWeek  ReportGroupBy = "week"
+	ReportGroupByMonth ReportGroupBy = "month"
+)
+
+// ReportRequest selects the data of a report. Type defaults to ReportTypeAccount and GroupBy to ReportGroupByDay.
+// TimezoneOffset is the offset of the timezone in hours used to group the data, e.g. 5.5 for India. Summaries ignore
+// Metric and GroupBy.
+type ReportRequest struct {
+	Metric         ReportMetric
+	Type           ReportType
+	ID             int64
+	Since          time.Time
+	Until          time.Time
+	GroupBy        ReportGroupBy
+	TimezoneOffset float64
+	BusinessHours  bool // only count the business hours of the inboxes for the time metrics
+}
+
+// ReportValue is a value of a report. Chatwoot returns counts as numbers and averages as strings, both are decoded.
+type ReportValue float64
+
+func (reportValue *ReportValue) UnmarshalJSON(data []byte) error {
+
+	if string(data) == "null" {
+		*reportValue = 0
+		return nil
+	}
+
+	var number float64
+
+	if err := json.Unmarshal(data, &number); err == nil {
+		*reportValue = ReportValue(number)
+		return nil
+	}
+
+	var text string
+
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	if text == "" {
+		*reportValue = 0
+		return nil
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+
+	if err != nil {
+		return fmt.Errorf("invalid report value %q: %w", text, err)
+	}
+
+	*reportValue = ReportValue(number)
+
+	return nil
+}
+
+// Duration interprets the value as seconds, which is the unit of the time metrics like avg_first_response_time.
+func (reportValue ReportValue) Duration() time.Duration {
+	return time.Duration(float64(reportValue) * float64(time.Second))
+}
+
+// ReportPoint is the value of a period, the timestamp is the start of the period. Count is the number of
+// conversations the averages are calculated from.
+type ReportPoint struct {
+	Timestamp Timestamp   `json:"timestamp"`
+	Value     ReportValue `json:"value"`
+	Count     int         `json:"count,omitempty"`
+}
+
+type ReportTimeSeries struct {
+	Metric  ReportMetric
+	Type    ReportType
+	ID      int64
+	GroupBy ReportGroupBy
+	Points  []ReportPoint
+}
+
+type ReportSummary struct {
+	ConversationsCount    int            `json:"conversations_count"`
+	IncomingMessagesCount int            `json:"incoming_messages_count"`
+	OutgoingMessagesCount int            `json:"outgoing_messages_count"`
+	AvgFirstResponseTime  ReportValue    `json:"avg_first_response_time"`
+	AvgResolutionTime     ReportValue    `json:"avg_resolution_time"`
+	ResolutionsCount      int            `json:"resolutions_count"`
+	ReplyTime             ReportValue    `json:"reply_time,omitempty"`
+	Previous              *ReportSummary `json:"previous,omitempty"` // the period of the same length before Since
+}
+
+// GetReport returns the time series of the metric.
+func (client *ChatwootClient) GetReport(accountId int64, agentToken string, reportRequest ReportRequest) (ReportTimeSeries, error) {
+
+	if reportRequest.Metric == "" {
+		return ReportTimeSeries{}, errors.New("report metric is empty")
+	}
+
+	query, err := reportRequest.query()
+
+	if err != nil {
+		return ReportTimeSeries{}, err
+	}
+
+	query.Set("metric", string(reportRequest.Metric))
+
+	groupBy := reportRequest.GroupBy
+	if groupBy == "" {
+		groupBy = ReportGroupByDay
+	}
+	query.Set("group_by", string(groupBy))
+
+	requestURL := fmt.Sprintf("%s/api/v2/accounts/%v/reports?%s", client.BaseUrl, accountId, query.Encode())
+
+	var points []ReportPoint
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &points); err != nil {
+		return ReportTimeSeries{}, err
+	}
+
+	return ReportTimeSeries{
+		Metric:  reportRequest.Metric,
+		Type:    ReportType(query.Get("type")),
+		ID:      reportRequest.ID,
+		GroupBy: groupBy,
+		Points:  points,
+	}, nil
+}
+
+// GetReportSummary returns the totals of the metrics for the period and the period before.
+func (client *ChatwootClient) GetReportSummary(accountId int64, agentToken string, reportRequest ReportRequest) (ReportSummary, error) {
+
+	query, err := reportRequest.query()
+
+	if err != nil {
+		return ReportSummary{}, err
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v2/accounts/%v/reports/summary?%s", client.BaseUrl, accountId, query.Encode())
+
+	var reportSummary ReportSummary
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &reportSummary); err != nil {
+		return ReportSummary{}, err
+	}
+
+	return reportSummary, nil
+}
+
+// query returns the parameters shared by reports and summaries.
+func (reportRequest ReportRequest) query() (url.Values, error) {
+
+	if reportRequest.Since.IsZero() || reportRequest.Until.IsZero() {
+		return nil, errors.New("report requires since and until")
+	}
+
+	if reportRequest.Until.Before(reportRequest.Since) {
+		return nil, errors.New("report until is before since")
+	}
+
+	reportType := reportRequest.Type
+	if reportType == "" {
+		reportType = ReportTypeAccount
+	}
+
+	query := url.Values{}
+	query.Set("type", string(reportType))
+
+	if reportType != ReportTypeAccount {
+		if reportRequest.ID == 0 {
+			return nil, fmt.Errorf("%s report requires an id", reportType)
+		}
+		query.Set("id", strconv.FormatInt(reportRequest.ID, 10))
+	}
+
+	query.Set("since", strconv.FormatInt(reportRequest.Since.Unix(), 10))
+	query.Set("until", strconv.FormatInt(reportRequest.Until.Unix(), 10))
+
+	if reportRequest.TimezoneOffset != 0 {
+		query.Set("timezone_offset", strconv.FormatFloat(reportRequest.TimezoneOffset, 'f', -1, 64))
+	}
+
+	if reportRequest.BusinessHours {
+		query.Set("business_hours", "true")
+	}
+
+	return query, nil
+}
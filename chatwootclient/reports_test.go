@@ -0,0 +1,83 @@
+package chatwootclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReports(t *testing.T) {
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		query := r.URL.Query()
+
+		if query.Get("type") != "inbox" || query.Get("id") != "3" || query.Get("since") != "1709251200" || query.Get("until") != "1709424000" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/accounts/1/reports":
+			if query.Get("metric") != "avg_first_response_time" || query.Get("group_by") != "day" || query.Get("timezone_offset") != "5.5" {
+				t.Errorf("unexpected request %s", r.URL)
+			}
+			w.Write([]byte(`[{"value": "90.5", "timestamp": 1709251200, "count": 4}, {"value": 0, "timestamp": 1709337600, "count": 0}]`))
+		case "/api/v2/accounts/1/reports/summary":
+			w.Write([]byte(`{"conversations_count": 12, "incoming_messages_count": 40, "outgoing_messages_count": 35,
+				"avg_first_response_time": "120.0", "avg_resolution_time": "3600", "resolutions_count": 10,
+				"previous": {"conversations_count": 8, "avg_first_response_time": null}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	reportRequest := ReportRequest{
+		Metric:         ReportMetricAvgFirstResponseTime,
+		Type:           ReportTypeInbox,
+		ID:             3,
+		Since:          since,
+		Until:          until,
+		TimezoneOffset: 5.5,
+	}
+
+	series, err := client.GetReport(1, "agent-token", reportRequest)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if series.GroupBy != ReportGroupByDay || len(series.Points) != 2 || !series.Points[0].Timestamp.Equal(since) ||
+		series.Points[0].Value.Duration() != 90500*time.Millisecond || series.Points[0].Count != 4 {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+
+	summary, err := client.GetReportSummary(1, "agent-token", reportRequest)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.ConversationsCount != 12 || summary.AvgResolutionTime.Duration() != time.Hour || summary.Previous.ConversationsCount != 8 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	if _, err := client.GetReport(1, "agent-token", ReportRequest{Metric: ReportMetricConversationsCount, Type: ReportTypeAgent, Since: since, Until: until}); err == nil {
+		t.Fatal("expected an error for an agent report without id")
+	}
+
+	if _, err := client.GetReportSummary(1, "agent-token", ReportRequest{Since: until, Until: since}); err == nil {
+		t.Fatal("expected an error for an invalid time range")
+	}
+
+}
@@ -0,0 +1,77 @@
+package chatwootclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// doJSONRequest sends a request with an optional JSON body to the Chatwoot API and decodes the JSON response into
+// responseBody when it is not nil. Responses with a status code outside of the 2xx range are returned as error.
+func (client *ChatwootClient) doJSONRequest(method string, requestURL string, token string, requestBody interface{}, responseBody interface{}) error {
+	return client.doJSONRequestWithContext(context.Background(), method, requestURL, token, requestBody, responseBody)
+}
+
+// doJSONRequestWithContext is doJSONRequest with a context that cancels the request.
+func (client *ChatwootClient) doJSONRequestWithContext(ctx context.Context, method string, requestURL string, token string, requestBody interface{}, responseBody interface{}) error {
+
+	var body io.Reader
+
+	if requestBody != nil {
+		requestBodyJSON, err := json.Marshal(requestBody)
+
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewBuffer(requestBodyJSON)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+
+	if err != nil {
+		return err
+	}
+
+	if requestBody != nil {
+		request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	}
+	request.Header.Add("api_access_token", token)
+
+	response, err := http.DefaultClient.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	bodyBytes, err := io.ReadAll(response.Body)
+
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("request failed: %s - Response body: %s", response.Status, string(bodyBytes))
+	}
+
+	if responseBody == nil || len(bytes.TrimSpace(bodyBytes)) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(bodyBytes, responseBody)
+}
+
+// Bool returns a pointer to the value, for optional fields of update requests.
+func Bool(value bool) *bool {
+	return &value
+}
+
+// String returns a pointer to the value, for optional fields of update requests.
+func String(value string) *string {
+	return &value
+}
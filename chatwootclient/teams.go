@@ -0,0 +1,251 @@
+package chatwootclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Team struct {
+	ID              int    `json:"id"`
+	AccountID       int    `json:"account_id,omitempty"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	AllowAutoAssign bool   `json:"allow_auto_assign"`
+	IsMember        bool   `json:"is_member,omitempty"`
+}
+
+type CreateTeamRequest struct {
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	AllowAutoAssign bool   `json:"allow_auto_assign"`
+}
+
+// UpdateTeamRequest only changes the fields that are set.
+type UpdateTeamRequest struct {
+	Name            string  `json:"name,omitempty"`
+	Description     *string `json:"description,omitempty"`
+	AllowAutoAssign *bool   `json:"allow_auto_assign,omitempty"`
+}
+
+func (client *ChatwootClient) ListTeams(accountId int64, agentToken string) ([]Team, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/teams", client.BaseUrl, accountId)
+
+	var teams []Team
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &teams); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+func (client *ChatwootClient) GetTeam(accountId int64, teamId int64, agentToken string) (Team, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/teams/%v", client.BaseUrl, accountId, teamId)
+
+	var team Team
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &team); err != nil {
+		return Team{}, err
+	}
+
+	return team, nil
+}
+
+func (client *ChatwootClient) CreateTeam(accountId int64, agentToken string, createTeamRequest CreateTeamRequest) (Team, error) {
+
+	if agentToken == "" {
+		return Team{}, errors.New("agentToken is empty. Creating teams requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/teams", client.BaseUrl, accountId)
+
+	var team Team
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, createTeamRequest, &team); err != nil {
+		return Team{}, err
+	}
+
+	return team, nil
+}
+
+func (client *ChatwootClient) UpdateTeam(accountId int64, teamId int64, agentToken string, updateTeamRequest UpdateTeamRequest) (Team, error) {
+
+	if agentToken == "" {
+		return Team{}, errors.New("agentToken is empty. Updating teams requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/teams/%v", client.BaseUrl, accountId, teamId)
+
+	var team Team
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, updateTeamRequest, &team); err != nil {
+		return Team{}, err
+	}
+
+	return team, nil
+}
+
+func (client *ChatwootClient) DeleteTeam(accountId int64, teamId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting teams requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/teams/%v", client.BaseUrl, accountId, teamId)
+
+	return client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil)
+}
+
+type TeamMembersRequest struct {
+	UserIDs []int64 `json:"user_ids"`
+}
+
+func (client *ChatwootClient) ListTeamMembers(accountId int64, teamId int64, agentToken string) ([]Agent, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/teams/%v/team_members", client.BaseUrl, accountId, teamId)
+
+	var agents []Agent
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &agents); err != nil {
+		return nil, err
+	}
+
+	return agents, nil
+}
+
+// AddTeamMembers adds the agents with the given user ids to the team and returns the added agents.
+func (client *ChatwootClient) AddTeamMembers(accountId int64, teamId int64, agentToken string, userIds []int64) ([]Agent, error) {
+
+	return client.changeTeamMembers(http.MethodPost, accountId, teamId, agentToken, userIds)
+}
+
+// RemoveTeamMembers removes the agents with the given user ids from the team.
+func (client *ChatwootClient) RemoveTeamMembers(accountId int64, teamId int64, agentToken string, userIds []int64) error {
+
+	_, err := client.changeTeamMembers(http.MethodDelete, accountId, teamId, agentToken, userIds)
+
+	return err
+}
+
+// SetTeamMembers replaces the members of the team with the agents with the given user ids.
+func (client *ChatwootClient) SetTeamMembers(accountId int64, teamId int64, agentToken string, userIds []int64) ([]Agent, error) {
+
+	return client.changeTeamMembers(http.MethodPatch, accountId, teamId, agentToken, userIds)
+}
+
+func (client *ChatwootClient) changeTeamMembers(method string, accountId int64, teamId int64, agentToken string, userIds []int64) ([]Agent, error) {
+
+	if agentToken == "" {
+		return nil, errors.New("agentToken is empty. Changing team members requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/teams/%v/team_members", client.BaseUrl, accountId, teamId)
+
+	var agents []Agent
+
+	if err := client.doJSONRequest(method, requestURL, agentToken, TeamMembersRequest{UserIDs: userIds}, &agents); err != nil {
+		return nil, err
+	}
+
+	return agents, nil
+}
+
+// TeamCache resolves team names to team ids, so that conversations can be assigned by team name. The teams are
+// loaded on first use and reloaded after the ttl expired or when a name is unknown, at most once per
+// MinReloadInterval. A zero ttl never expires.
+type TeamCache struct {
+	client     *ChatwootClient
+	accountId  int64
+	agentToken string
+	ttl        time.Duration
+
+	teams reloadingCache[map[string]Team]
+}
+
+func NewTeamCache(client *ChatwootClient, accountId int64, agentToken string, ttl time.Duration) *TeamCache {
+	return &TeamCache{
+		client:     client,
+		accountId:  accountId,
+		agentToken: agentToken,
+		ttl:        ttl,
+	}
+}
+
+// Refresh reloads the teams from Chatwoot.
+func (teamCache *TeamCache) Refresh() error {
+
+	teamCache.teams.mutex.Lock()
+	defer teamCache.teams.mutex.Unlock()
+
+	_, err := teamCache.teams.reload(teamCache.load)
+
+	return err
+}
+
+// load returns the teams by lower case name.
+func (teamCache *TeamCache) load() (map[string]Team, error) {
+
+	teams, err := teamCache.client.ListTeams(teamCache.accountId, teamCache.agentToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	teamsByName := make(map[string]Team, len(teams))
+
+	for _, team := range teams {
+		teamsByName[strings.ToLower(team.Name)] = team
+	}
+
+	return teamsByName, nil
+}
+
+// Team returns the team with the given name, names are compared case insensitive like in Chatwoot.
+func (teamCache *TeamCache) Team(name string) (Team, error) {
+
+	teamCache.teams.mutex.Lock()
+	defer teamCache.teams.mutex.Unlock()
+
+	key := strings.ToLower(name)
+
+	teams, err := teamCache.teams.get(teamCache.ttl, teamCache.load, func(teams map[string]Team) bool {
+		_, ok := teams[key]
+		return ok
+	})
+
+	if err != nil {
+		return Team{}, err
+	}
+
+	team, ok := teams[key]
+
+	if !ok {
+		return Team{}, fmt.Errorf("team %s does not exist", name)
+	}
+
+	return team, nil
+}
+
+func (teamCache *TeamCache) TeamID(name string) (int, error) {
+
+	team, err := teamCache.Team(name)
+
+	return team.ID, err
+}
+
+// AssignTeam assigns the conversation to the team with the given name.
+func (teamCache *TeamCache) AssignTeam(conversationId int64, name string) error {
+
+	teamId, err := teamCache.TeamID(name)
+
+	if err != nil {
+		return err
+	}
+
+	return teamCache.client.AssignTeam(teamCache.accountId, conversationId, teamCache.agentToken, teamId)
+}
@@ -0,0 +1,106 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTeamCacheAssignTeam(t *testing.T) {
+
+	listTeamsCalls := 0
+	teams := `[{"id": 1, "name": "sales"}]`
+	var assignment map[string]int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		switch r.URL.Path {
+		case "/api/v1/accounts/1/teams":
+			listTeamsCalls++
+			w.Write([]byte(teams))
+		case "/api/v1/accounts/1/conversations/7/assignments":
+			json.NewDecoder(r.Body).Decode(&assignment)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	teamCache := NewTeamCache(&client, 1, "agent-token", 0)
+
+	if err := teamCache.AssignTeam(7, "Sales"); err != nil {
+		t.Fatal(err)
+	}
+
+	if assignment["team_id"] != 1 {
+		t.Fatalf("unexpected assignment: %v", assignment)
+	}
+
+	if _, err := teamCache.TeamID("sales"); err != nil || listTeamsCalls != 1 {
+		t.Fatalf("expected the teams to be cached, %d calls: %v", listTeamsCalls, err)
+	}
+
+	teams = `[{"id": 1, "name": "sales"}, {"id": 2, "name": "support"}]`
+
+	if _, err := teamCache.TeamID("support"); err == nil || listTeamsCalls != 1 {
+		t.Fatalf("expected the teams not to be reloaded within MinReloadInterval, %d calls: %v", listTeamsCalls, err)
+	}
+
+	teamCache.teams.loadedAt = teamCache.teams.loadedAt.Add(-MinReloadInterval)
+
+	if teamId, err := teamCache.TeamID("support"); err != nil || teamId != 2 || listTeamsCalls != 2 {
+		t.Fatalf("expected the teams to be reloaded for unknown names, %d calls: %v", listTeamsCalls, err)
+	}
+
+	if _, err := teamCache.TeamID("billing"); err == nil || listTeamsCalls != 2 {
+		t.Fatalf("expected an error for an unknown team without reload, %d calls: %v", listTeamsCalls, err)
+	}
+
+}
+
+func TestTeamMembers(t *testing.T) {
+
+	var teamMembersRequest TeamMembersRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path != "/api/v1/accounts/1/teams/2/team_members" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&teamMembersRequest)
+		}
+
+		w.Write([]byte(`[{"id": 3, "name": "Jane"}]`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	agents, err := client.AddTeamMembers(1, 2, "agent-token", []int64{3})
+
+	if err != nil || len(agents) != 1 || len(teamMembersRequest.UserIDs) != 1 || teamMembersRequest.UserIDs[0] != 3 {
+		t.Fatalf("unexpected result %+v: %v", agents, err)
+	}
+
+	agents, err = client.ListTeamMembers(1, 2, "agent-token")
+
+	if err != nil || len(agents) != 1 || agents[0].Name != "Jane" {
+		t.Fatalf("unexpected members %+v: %v", agents, err)
+	}
+
+}
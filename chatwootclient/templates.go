@@ -0,0 +1,148 @@
+package chatwootclient
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TemplateParams describes an approved WhatsApp message template that is sent instead of free text. WhatsApp only
+// accepts templates once the 24h customer service window of a conversation is closed.
+type TemplateParams struct {
+	Name            string            `json:"name"`
+	Category        string            `json:"category,omitempty"`
+	Language        string            `json:"language"`
+	ProcessedParams map[string]string `json:"processed_params"`
+	lastParam       int               // the placeholder last set by AddParam
+}
+
+// NewTemplateParams starts building the template params of the template with the given name, category
+// (e.g. MARKETING, UTILITY) and language code (e.g. en_US). Parameters are added using AddParam or SetParam.
+func NewTemplateParams(name string, category string, language string) *TemplateParams {
+	return &TemplateParams{
+		Name:            name,
+		Category:        category,
+		Language:        language,
+		ProcessedParams: map[string]string{},
+	}
+}
+
+// AddParam sets the value of the next positional placeholder, i.e. the first call sets {{1}}, the second {{2}}, ...
+// Placeholders that already have a value set by SetParam are skipped.
+func (templateParams *TemplateParams) AddParam(value string) *TemplateParams {
+
+	for {
+		templateParams.lastParam++
+		if _, ok := templateParams.ProcessedParams[strconv.Itoa(templateParams.lastParam)]; !ok {
+			break
+		}
+	}
+
+	return templateParams.SetParam(strconv.Itoa(templateParams.lastParam), value)
+}
+
+// SetParam sets the value of the placeholder with the given key, e.g. "1" for {{1}}.
+func (templateParams *TemplateParams) SetParam(key string, value string) *TemplateParams {
+	if templateParams.ProcessedParams == nil {
+		templateParams.ProcessedParams = map[string]string{}
+	}
+	templateParams.ProcessedParams[key] = value
+	return templateParams
+}
+
+// NewTemplateMessageRequest creates an outgoing message request that sends the given template. The content is shown
+// in Chatwoot and should contain the rendered template text.
+func NewTemplateMessageRequest(content string, templateParams *TemplateParams) CreateNewMessageRequest {
+	return CreateNewMessageRequest{
+		Content:        content,
+		MessageType:    "outgoing",
+		Private:        false,
+		TemplateParams: templateParams,
+	}
+}
+
+func (client *ChatwootClient) CreateTemplateMessage(accountId int64, conversationId int64, agentBotToken string, content string, templateParams *TemplateParams) (CreateNewMessageResponse, error) {
+
+	return client.CreateNewMessage(accountId, conversationId, agentBotToken, NewTemplateMessageRequest(content, templateParams))
+
+}
+
+type MessageTemplate struct {
+	ID         string                     `json:"id"`
+	Name       string                     `json:"name"`
+	Status     string                     `json:"status"`
+	Category   string                     `json:"category"`
+	Language   string                     `json:"language"`
+	Components []MessageTemplateComponent `json:"components"`
+}
+
+type MessageTemplateComponent struct {
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+var templatePlaceholderPattern = regexp.MustCompile(`\{\{\s*(\d+)\s*\}\}`)
+
+// ParamCount returns the number of distinct positional placeholders used by the text components of the template.
+func (messageTemplate MessageTemplate) ParamCount() int {
+
+	placeholders := map[string]bool{}
+
+	for _, component := range messageTemplate.Components {
+		for _, match := range templatePlaceholderPattern.FindAllStringSubmatch(component.Text, -1) {
+			placeholders[match[1]] = true
+		}
+	}
+
+	return len(placeholders)
+}
+
+// ListMessageTemplates returns the message templates synced by Chatwoot for the given WhatsApp inbox.
+func (client *ChatwootClient) ListMessageTemplates(accountId int64, inboxId int64, agentToken string) ([]MessageTemplate, error) {
+
+	inbox, err := client.GetInbox(accountId, inboxId, agentToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return inbox.MessageTemplates, nil
+}
+
+// ValidateTemplateParams checks that the templates contain an approved template matching the name and language of the
+// template params and that a value is provided for each of its placeholders.
+func ValidateTemplateParams(messageTemplates []MessageTemplate, templateParams *TemplateParams) error {
+
+	if templateParams == nil {
+		return fmt.Errorf("template params are missing")
+	}
+
+	for _, messageTemplate := range messageTemplates {
+
+		if messageTemplate.Name != templateParams.Name || messageTemplate.Language != templateParams.Language {
+			continue
+		}
+
+		if messageTemplate.Status != "" && !strings.EqualFold(messageTemplate.Status, "APPROVED") {
+			return fmt.Errorf("template %s (%s) is not approved: %s", templateParams.Name, templateParams.Language, messageTemplate.Status)
+		}
+
+		paramCount := messageTemplate.ParamCount()
+
+		if len(templateParams.ProcessedParams) != paramCount {
+			return fmt.Errorf("template %s (%s) expects %d params, got %d", templateParams.Name, templateParams.Language, paramCount, len(templateParams.ProcessedParams))
+		}
+
+		for i := 1; i <= paramCount; i++ {
+			if _, ok := templateParams.ProcessedParams[strconv.Itoa(i)]; !ok {
+				return fmt.Errorf("template %s (%s) is missing a value for param {{%d}}", templateParams.Name, templateParams.Language, i)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("template %s (%s) does not exist", templateParams.Name, templateParams.Language)
+}
@@ -0,0 +1,93 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCreateTemplateMessage(t *testing.T) {
+
+	var createNewMessageRequest CreateNewMessageRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		json.NewDecoder(r.Body).Decode(&createNewMessageRequest)
+
+		w.Write([]byte(`{"id": 1, "content": "Hi Jane"}`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	templateParams := NewTemplateParams("order_confirmation", "UTILITY", "en_US").AddParam("Jane").AddParam("#1001")
+
+	_, err := client.CreateTemplateMessage(1, 2, "", "Hi Jane", templateParams)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if createNewMessageRequest.TemplateParams == nil || createNewMessageRequest.TemplateParams.ProcessedParams["2"] != "#1001" {
+		t.Fatalf("unexpected template params: %+v", createNewMessageRequest.TemplateParams)
+	}
+
+}
+
+func TestValidateTemplateParams(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path != "/api/v1/accounts/1/inboxes/3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{"id": 3, "message_templates": [{"name": "order_confirmation", "status": "APPROVED", "language": "en_US",
+			"components": [{"type": "HEADER", "format": "TEXT", "text": "Order {{2}}"}, {"type": "BODY", "text": "Hi {{1}}, your order {{2}} is confirmed."}]}]}`))
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	messageTemplates, err := client.ListMessageTemplates(1, 3, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateTemplateParams(messageTemplates, NewTemplateParams("order_confirmation", "UTILITY", "en_US").AddParam("Jane").AddParam("#1001")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateTemplateParams(messageTemplates, NewTemplateParams("order_confirmation", "UTILITY", "en_US").AddParam("Jane")); err == nil {
+		t.Fatal("expected an error for a missing param")
+	}
+
+	if err := ValidateTemplateParams(messageTemplates, NewTemplateParams("order_shipped", "UTILITY", "en_US")); err == nil {
+		t.Fatal("expected an error for an unknown template")
+	}
+
+}
+
+func TestTemplateParamsAddParam(t *testing.T) {
+
+	templateParams := NewTemplateParams("order_confirmation", "UTILITY", "en_US").SetParam("2", "#1001").AddParam("Jane").AddParam("DHL")
+
+	expected := map[string]string{"1": "Jane", "2": "#1001", "3": "DHL"}
+
+	if !reflect.DeepEqual(templateParams.ProcessedParams, expected) {
+		t.Fatalf("unexpected params %v", templateParams.ProcessedParams)
+	}
+
+}
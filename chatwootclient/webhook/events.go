@@ -0,0 +1,170 @@
+// Package webhook receives Chatwoot webhook and agent bot callbacks, decodes them into typed events and dispatches
+// them to the registered handler funcs.
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+// Event is implemented by all decoded events.
+type Event interface {
+	EventName() chatwootclient.WebhookEvent
+}
+
+type Account struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type AttributeChange struct {
+	PreviousValue interface{} `json:"previous_value"`
+	CurrentValue  interface{} `json:"current_value"`
+}
+
+// ChangedAttributes lists the attributes changed by an update, e.g. [{"status": {"previous_value": "open", ...}}].
+type ChangedAttributes []map[string]AttributeChange
+
+// Get returns the change of the attribute with the given name.
+func (changedAttributes ChangedAttributes) Get(name string) (AttributeChange, bool) {
+	for _, changedAttribute := range changedAttributes {
+		if change, ok := changedAttribute[name]; ok {
+			return change, true
+		}
+	}
+	return AttributeChange{}, false
+}
+
+// MessageEvent is sent for message_created and message_updated.
+type MessageEvent struct {
+	chatwootclient.Message
+	Event             chatwootclient.WebhookEvent `json:"event"`
+	Account           Account                     `json:"account"`
+	Inbox             chatwootclient.Inbox        `json:"inbox"`
+	Conversation      chatwootclient.Conversation `json:"conversation"`
+	ChangedAttributes ChangedAttributes           `json:"changed_attributes,omitempty"`
+}
+
+func (event *MessageEvent) EventName() chatwootclient.WebhookEvent { return event.Event }
+
+// ConversationEvent is sent for conversation_created, conversation_status_changed and conversation_updated as well as
+// conversation_opened and conversation_resolved for agent bots.
+type ConversationEvent struct {
+	chatwootclient.Conversation
+	Event             chatwootclient.WebhookEvent `json:"event"`
+	Account           Account                     `json:"account"`
+	ContactInbox      *ContactInbox               `json:"contact_inbox,omitempty"`
+	ChangedAttributes ChangedAttributes           `json:"changed_attributes,omitempty"`
+}
+
+func (event *ConversationEvent) EventName() chatwootclient.WebhookEvent { return event.Event }
+
+type ContactInbox struct {
+	ID        int    `json:"id"`
+	ContactID int    `json:"contact_id"`
+	InboxID   int    `json:"inbox_id"`
+	SourceID  string `json:"source_id"`
+}
+
+// ContactEvent is sent for contact_created and contact_updated.
+type ContactEvent struct {
+	chatwootclient.Contact
+	Event             chatwootclient.WebhookEvent `json:"event"`
+	Account           Account                     `json:"account"`
+	ChangedAttributes ChangedAttributes           `json:"changed_attributes,omitempty"`
+}
+
+func (event *ContactEvent) EventName() chatwootclient.WebhookEvent { return event.Event }
+
+// WebwidgetTriggeredEvent is sent when a visitor opens the website widget.
+type WebwidgetTriggeredEvent struct {
+	Event               chatwootclient.WebhookEvent  `json:"event"`
+	ID                  int                          `json:"id"`
+	SourceID            string                       `json:"source_id"`
+	Account             Account                      `json:"account"`
+	Inbox               chatwootclient.Inbox         `json:"inbox"`
+	Contact             chatwootclient.Contact       `json:"contact"`
+	CurrentConversation *chatwootclient.Conversation `json:"current_conversation,omitempty"`
+	EventInfo           map[string]interface{}       `json:"event_info,omitempty"`
+}
+
+func (event *WebwidgetTriggeredEvent) EventName() chatwootclient.WebhookEvent { return event.Event }
+
+// TypingEvent is sent for conversation_typing_on and conversation_typing_off.
+type TypingEvent struct {
+	Event        chatwootclient.WebhookEvent   `json:"event"`
+	Account      Account                       `json:"account"`
+	Conversation chatwootclient.Conversation   `json:"conversation"`
+	User         *chatwootclient.MessageSender `json:"user,omitempty"`
+	IsPrivate    bool                          `json:"is_private"`
+}
+
+func (event *TypingEvent) EventName() chatwootclient.WebhookEvent { return event.Event }
+
+// UnknownEvent is returned for events this package does not know, the payload is kept as is.
+type UnknownEvent struct {
+	Event   chatwootclient.WebhookEvent
+	Payload json.RawMessage
+}
+
+func (event *UnknownEvent) EventName() chatwootclient.WebhookEvent { return event.Event }
+
+var ErrMissingEvent = errors.New("payload has no event")
+
+// Decode decodes a webhook payload into the typed event matching its event name.
+func Decode(payload []byte) (Event, error) {
+
+	var envelope struct {
+		Event chatwootclient.WebhookEvent `json:"event"`
+	}
+
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, err
+	}
+
+	if envelope.Event == "" {
+		return nil, ErrMissingEvent
+	}
+
+	return DecodeEvent(envelope.Event, payload)
+}
+
+// DecodeEvent decodes the data of an event whose name is known, e.g. because it is sent separately like by the
+// realtime API, into the typed event for the name.
+func DecodeEvent(eventName chatwootclient.WebhookEvent, data []byte) (Event, error) {
+
+	event := newEvent(eventName)
+
+	if unknownEvent, ok := event.(*UnknownEvent); ok {
+		unknownEvent.Payload = append(json.RawMessage(nil), data...)
+		return unknownEvent, nil
+	}
+
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// newEvent returns an empty event of the type used for the event name.
+func newEvent(eventName chatwootclient.WebhookEvent) Event {
+
+	switch eventName {
+	case chatwootclient.EventMessageCreated, chatwootclient.EventMessageUpdated:
+		return &MessageEvent{Event: eventName}
+	case chatwootclient.EventConversationCreated, chatwootclient.EventConversationStatusChanged, chatwootclient.EventConversationUpdated,
+		chatwootclient.EventConversationOpened, chatwootclient.EventConversationResolved:
+		return &ConversationEvent{Event: eventName}
+	case chatwootclient.EventContactCreated, chatwootclient.EventContactUpdated:
+		return &ContactEvent{Event: eventName}
+	case chatwootclient.EventWebwidgetTriggered:
+		return &WebwidgetTriggeredEvent{Event: eventName}
+	case chatwootclient.EventConversationTypingOn, chatwootclient.EventConversationTypingOff:
+		return &TypingEvent{Event: eventName}
+	}
+
+	return &UnknownEvent{Event: eventName}
+}
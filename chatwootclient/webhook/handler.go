@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+// MaxPayloadSize is the maximum size of a webhook payload accepted by the Handler.
+const MaxPayloadSize = 5 << 20
+
+// HandlerFunc handles a decoded event. Returning an error makes the Handler respond with 500.
+type HandlerFunc func(event Event) error
+
+// Handler is an http.Handler that decodes Chatwoot webhook requests and dispatches the events to the handler funcs
+// registered for the event. Events without registered handler funcs are acknowledged and dropped.
+//
+// If Secret is set, requests have to be signed with it: requests without or with an invalid signature and requests
+// whose timestamp is older or newer than TimestampTolerance (DefaultTimestampTolerance if zero, a negative value
+// disables the check) are rejected with 401. If ReplayCache is set as well, deliveries that have already been
+// received are rejected with 409. Deliveries that fail are removed from the ReplayCache, so that they can be retried.
+//
+// If DedupeStore is set, redelivered events are acknowledged without dispatching them again, see DedupeKey. They are
+// remembered for DedupeTTL (DefaultDedupeTTL if zero) unless they failed. If Queue is set, the events are enqueued
+// instead of being dispatched while handling the request. The fields have to be set before the handler serves
+// requests.
+type Handler struct {
+	Secret             string
+	TimestampTolerance time.Duration
+	ReplayCache        ReplayCache
+	DedupeStore        ReplayCache
+	DedupeTTL          time.Duration
+	Queue              *Queue
+
+	mutex       sync.RWMutex
+	handlers    map[chatwootclient.WebhookEvent][]HandlerFunc
+	anyHandlers []HandlerFunc
+	now         func() time.Time
+}
+
+func NewHandler() *Handler {
+	return &Handler{
+		handlers: map[chatwootclient.WebhookEvent][]HandlerFunc{},
+		now:      time.Now,
+	}
+}
+
+// NewSignedHandler returns a handler that verifies the signatures of the requests with the secret and rejects
+// replayed deliveries using a MemoryReplayCache.
+func NewSignedHandler(secret string) *Handler {
+
+	handler := NewHandler()
+	handler.Secret = secret
+	handler.ReplayCache = NewMemoryReplayCache()
+
+	return handler
+}
+
+// On registers a handler func for the given event.
+func (handler *Handler) On(eventName chatwootclient.WebhookEvent, handlerFunc HandlerFunc) {
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.handlers == nil {
+		handler.handlers = map[chatwootclient.WebhookEvent][]HandlerFunc{}
+	}
+
+	handler.handlers[eventName] = append(handler.handlers[eventName], handlerFunc)
+}
+
+// OnAny registers a handler func that is called for all events, including unknown events.
+func (handler *Handler) OnAny(handlerFunc HandlerFunc) {
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.anyHandlers = append(handler.anyHandlers, handlerFunc)
+}
+
+func (handler *Handler) OnMessageCreated(handlerFunc func(event *MessageEvent) error) {
+	handler.On(chatwootclient.EventMessageCreated, messageHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnMessageUpdated(handlerFunc func(event *MessageEvent) error) {
+	handler.On(chatwootclient.EventMessageUpdated, messageHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnConversationCreated(handlerFunc func(event *ConversationEvent) error) {
+	handler.On(chatwootclient.EventConversationCreated, conversationHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnConversationStatusChanged(handlerFunc func(event *ConversationEvent) error) {
+	handler.On(chatwootclient.EventConversationStatusChanged, conversationHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnConversationUpdated(handlerFunc func(event *ConversationEvent) error) {
+	handler.On(chatwootclient.EventConversationUpdated, conversationHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnConversationOpened(handlerFunc func(event *ConversationEvent) error) {
+	handler.On(chatwootclient.EventConversationOpened, conversationHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnConversationResolved(handlerFunc func(event *ConversationEvent) error) {
+	handler.On(chatwootclient.EventConversationResolved, conversationHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnContactCreated(handlerFunc func(event *ContactEvent) error) {
+	handler.On(chatwootclient.EventContactCreated, contactHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnContactUpdated(handlerFunc func(event *ContactEvent) error) {
+	handler.On(chatwootclient.EventContactUpdated, contactHandlerFunc(handlerFunc))
+}
+
+func (handler *Handler) OnWebwidgetTriggered(handlerFunc func(event *WebwidgetTriggeredEvent) error) {
+	handler.On(chatwootclient.EventWebwidgetTriggered, func(event Event) error {
+		return handlerFunc(event.(*WebwidgetTriggeredEvent))
+	})
+}
+
+// OnTyping registers a handler func for conversation_typing_on and conversation_typing_off.
+func (handler *Handler) OnTyping(handlerFunc func(event *TypingEvent) error) {
+	typingHandlerFunc := func(event Event) error {
+		return handlerFunc(event.(*TypingEvent))
+	}
+	handler.On(chatwootclient.EventConversationTypingOn, typingHandlerFunc)
+	handler.On(chatwootclient.EventConversationTypingOff, typingHandlerFunc)
+}
+
+func messageHandlerFunc(handlerFunc func(event *MessageEvent) error) HandlerFunc {
+	return func(event Event) error {
+		return handlerFunc(event.(*MessageEvent))
+	}
+}
+
+func conversationHandlerFunc(handlerFunc func(event *ConversationEvent) error) HandlerFunc {
+	return func(event Event) error {
+		return handlerFunc(event.(*ConversationEvent))
+	}
+}
+
+func contactHandlerFunc(handlerFunc func(event *ContactEvent) error) HandlerFunc {
+	return func(event Event) error {
+		return handlerFunc(event.(*ContactEvent))
+	}
+}
+
+// Dispatch calls the handler funcs registered for the event unless it is a duplicate. It is used by the HTTP handler,
+// but also allows other event sources to deliver events to the same handler funcs. All handler funcs are called, the
+// first error is returned. Events that fail are removed from the DedupeStore, so that a redelivery is dispatched again.
+func (handler *Handler) Dispatch(event Event) error {
+
+	duplicate, err := handler.duplicate(event)
+
+	if err != nil || duplicate {
+		return err
+	}
+
+	if err := handler.dispatch(event); err != nil {
+		handler.forget(event)
+		return err
+	}
+
+	return nil
+}
+
+// Deliver enqueues the event if the handler has a Queue, otherwise it dispatches the event. Event sources like the
+// HTTP handler use it to respect the configuration of the handler.
+func (handler *Handler) Deliver(event Event) error {
+
+	if handler.Queue != nil {
+		return handler.Queue.Enqueue(event)
+	}
+
+	return handler.Dispatch(event)
+}
+
+func (handler *Handler) dispatch(event Event) error {
+
+	handler.mutex.RLock()
+	handlerFuncs := append(append([]HandlerFunc(nil), handler.handlers[event.EventName()]...), handler.anyHandlers...)
+	handler.mutex.RUnlock()
+
+	var firstErr error
+
+	for _, handlerFunc := range handlerFuncs {
+		if err := handlerFunc(event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxPayloadSize))
+
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read payload", http.StatusBadRequest)
+		return
+	}
+
+	deliveryID, err := handler.verify(payload, r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), r.Header.Get(DeliveryHeader))
+
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrReplayed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrInvalidTimestamp):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		default:
+			http.Error(w, "failed to verify request", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	event, err := Decode(payload)
+
+	if err != nil {
+		handler.forgetDelivery(deliveryID)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.Deliver(event); err != nil {
+		handler.forgetDelivery(deliveryID)
+		switch {
+		case errors.Is(err, ErrQueueFull), errors.Is(err, ErrQueueClosed):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			http.Error(w, "failed to handle event", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
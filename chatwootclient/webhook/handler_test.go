@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+const messageCreatedPayload = `{
+	"event": "message_created",
+	"id": 42,
+	"content": "Where is my order?",
+	"content_type": "text",
+	"message_type": "incoming",
+	"private": false,
+	"created_at": "2024-03-01T10:00:00.000Z",
+	"sender": {"id": 7, "name": "Jane Doe", "email": "jane@example.com", "type": "contact"},
+	"account": {"id": 1, "name": "Shop"},
+	"inbox": {"id": 3, "name": "Website"},
+	"conversation": {"id": 9, "inbox_id": 3, "status": "pending", "labels": ["vip"],
+		"meta": {"sender": {"id": 7, "name": "Jane Doe"}, "assignee": null}}
+}`
+
+const conversationStatusChangedPayload = `{
+	"event": "conversation_status_changed",
+	"id": 9,
+	"inbox_id": 3,
+	"status": "resolved",
+	"account": {"id": 1, "name": "Shop"},
+	"changed_attributes": [{"status": {"previous_value": "open", "current_value": "resolved"}}],
+	"meta": {"sender": {"id": 7, "name": "Jane Doe"}}
+}`
+
+func TestHandler(t *testing.T) {
+
+	handler := NewHandler()
+
+	var messageEvent *MessageEvent
+	var conversationEvent *ConversationEvent
+	var anyEvents []chatwootclient.WebhookEvent
+
+	handler.OnMessageCreated(func(event *MessageEvent) error {
+		messageEvent = event
+		return nil
+	})
+
+	handler.OnConversationStatusChanged(func(event *ConversationEvent) error {
+		conversationEvent = event
+		return errors.New("failed")
+	})
+
+	handler.OnAny(func(event Event) error {
+		anyEvents = append(anyEvents, event.EventName())
+		return nil
+	})
+
+	post := func(payload string) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/chatwoot", strings.NewReader(payload)))
+		return recorder.Code
+	}
+
+	if status := post(messageCreatedPayload); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	if messageEvent == nil || messageEvent.ID != 42 || messageEvent.MessageType != chatwootclient.MessageTypeIncoming || messageEvent.Sender.Name != "Jane Doe" ||
+		messageEvent.Account.ID != 1 || messageEvent.Inbox.ID != 3 || messageEvent.Conversation.ID != 9 || messageEvent.Conversation.Meta.Sender.ID != 7 {
+		t.Fatalf("unexpected message event: %+v", messageEvent)
+	}
+
+	if status := post(conversationStatusChangedPayload); status != http.StatusInternalServerError {
+		t.Fatalf("expected the handler error to be reported, got status %d", status)
+	}
+
+	if change, ok := conversationEvent.ChangedAttributes.Get("status"); !ok || change.PreviousValue != "open" || conversationEvent.Status != chatwootclient.ConversationStatusResolved {
+		t.Fatalf("unexpected conversation event: %+v", conversationEvent)
+	}
+
+	if status := post(`{"event": "conversation_bot_handoff", "id": 9}`); status != http.StatusOK {
+		t.Fatalf("expected unknown events to be acknowledged, got status %d", status)
+	}
+
+	if status := post(`{"id": 9}`); status != http.StatusBadRequest {
+		t.Fatalf("expected payloads without event to be rejected, got status %d", status)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/chatwoot", nil))
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status %d", recorder.Code)
+	}
+
+	if len(anyEvents) != 3 || anyEvents[2] != "conversation_bot_handoff" {
+		t.Fatalf("unexpected events: %v", anyEvents)
+	}
+
+}
@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+// DefaultDedupeTTL is how long delivered events are remembered if the Handler has no DedupeTTL.
+const DefaultDedupeTTL = time.Hour
+
+// DefaultQueueSize is the number of events a conversation queue buffers if the Queue has no QueueSize.
+const DefaultQueueSize = 100
+
+var (
+	ErrQueueFull   = errors.New("conversation queue is full")
+	ErrQueueClosed = errors.New("queue is closed")
+)
+
+// DedupeKey returns the key used to detect redelivered events, the event name and the ID of the created message,
+// conversation or contact. Updates can legitimately be sent several times for the same ID, for them and for unknown
+// events an empty string is returned and they are never deduplicated.
+func DedupeKey(event Event) string {
+
+	switch event := event.(type) {
+	case *MessageEvent:
+		if event.Event == chatwootclient.EventMessageCreated && event.ID != 0 {
+			return fmt.Sprintf("%s:%d", event.Event, event.ID)
+		}
+	case *ConversationEvent:
+		if event.Event == chatwootclient.EventConversationCreated && event.ID != 0 {
+			return fmt.Sprintf("%s:%d", event.Event, event.ID)
+		}
+	case *ContactEvent:
+		if event.Event == chatwootclient.EventContactCreated && event.ID != 0 {
+			return fmt.Sprintf("%s:%d", event.Event, event.ID)
+		}
+	}
+
+	return ""
+}
+
+// duplicate reports whether the event has already been dispatched, it always returns false without DedupeStore.
+func (handler *Handler) duplicate(event Event) (bool, error) {
+
+	if handler.DedupeStore == nil {
+		return false, nil
+	}
+
+	key := DedupeKey(event)
+
+	if key == "" {
+		return false, nil
+	}
+
+	ttl := handler.DedupeTTL
+	if ttl <= 0 {
+		ttl = DefaultDedupeTTL
+	}
+
+	now := time.Now()
+	if handler.now != nil {
+		now = handler.now()
+	}
+
+	return handler.DedupeStore.CheckAndStore(key, now.Add(ttl))
+}
+
+// forget removes the event from the DedupeStore after it failed, so that a redelivery is dispatched again.
+func (handler *Handler) forget(event Event) {
+
+	if handler.DedupeStore == nil {
+		return
+	}
+
+	if key := DedupeKey(event); key != "" {
+		// the event failed anyway, if removing fails as well the redelivery is dropped
+		handler.DedupeStore.Remove(key)
+	}
+}
+
+// ConversationID returns the ID of the conversation the event belongs to or 0 if it belongs to no conversation.
+func ConversationID(event Event) int {
+
+	switch event := event.(type) {
+	case *MessageEvent:
+		if event.Conversation.ID != 0 {
+			return event.Conversation.ID
+		}
+		return event.ConversationID
+	case *ConversationEvent:
+		return event.ID
+	case *TypingEvent:
+		return event.Conversation.ID
+	case *WebwidgetTriggeredEvent:
+		if event.CurrentConversation != nil {
+			return event.CurrentConversation.ID
+		}
+	}
+
+	return 0
+}
+
+// Queue dispatches the events of a Handler asynchronously. The events of a conversation are dispatched sequentially in
+// the order they were enqueued, events of different conversations are dispatched in parallel. Events that belong to
+// no conversation share a queue. The goroutine of a conversation exits when its queue is empty.
+//
+// Assign the queue to Handler.Queue to acknowledge webhook requests as soon as the event is enqueued. The errors of the
+// handler funcs can no longer be reported to Chatwoot then, they are passed to ErrorHandler instead.
+type Queue struct {
+	QueueSize    int
+	ErrorHandler func(event Event, err error)
+
+	handler   *Handler
+	mutex     sync.Mutex
+	queues    map[int]chan Event
+	waitGroup sync.WaitGroup
+	closed    bool
+}
+
+func NewQueue(handler *Handler) *Queue {
+	return &Queue{
+		handler: handler,
+		queues:  map[int]chan Event{},
+	}
+}
+
+// Enqueue adds the event to the queue of its conversation. Events already dispatched according to the DedupeStore of
+// the handler are dropped. ErrQueueFull is returned if the conversation has QueueSize events pending. Events that are
+// not enqueued or whose handler funcs fail are removed from the DedupeStore again.
+func (queue *Queue) Enqueue(event Event) error {
+
+	duplicate, err := queue.handler.duplicate(event)
+
+	if err != nil || duplicate {
+		return err
+	}
+
+	if err := queue.enqueue(event); err != nil {
+		queue.handler.forget(event)
+		return err
+	}
+
+	return nil
+}
+
+func (queue *Queue) enqueue(event Event) error {
+
+	conversationId := ConversationID(event)
+
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.closed {
+		return ErrQueueClosed
+	}
+
+	events, ok := queue.queues[conversationId]
+
+	if !ok {
+		queueSize := queue.QueueSize
+		if queueSize <= 0 {
+			queueSize = DefaultQueueSize
+		}
+		events = make(chan Event, queueSize)
+		queue.queues[conversationId] = events
+		queue.waitGroup.Add(1)
+		go queue.work(conversationId, events)
+	}
+
+	select {
+	case events <- event:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting events and waits until the pending events are dispatched.
+func (queue *Queue) Close() {
+
+	queue.mutex.Lock()
+	queue.closed = true
+	queue.mutex.Unlock()
+
+	queue.waitGroup.Wait()
+}
+
+func (queue *Queue) work(conversationId int, events chan Event) {
+
+	defer queue.waitGroup.Done()
+
+	for {
+		select {
+		case event := <-events:
+			if err := queue.handler.dispatch(event); err != nil {
+				queue.handler.forget(event)
+				if queue.ErrorHandler != nil {
+					queue.ErrorHandler(event, err)
+				}
+			}
+		default:
+			// events are only added while holding the mutex, the queue is empty if it is empty now
+			queue.mutex.Lock()
+			if len(events) == 0 {
+				delete(queue.queues, conversationId)
+				queue.mutex.Unlock()
+				return
+			}
+			queue.mutex.Unlock()
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ga-commerce/chatwoot-golang-client/chatwootclient"
+)
+
+func messageCreated(messageId int, conversationId int) *MessageEvent {
+	event := &MessageEvent{Event: chatwootclient.EventMessageCreated}
+	event.ID = messageId
+	event.Conversation.ID = conversationId
+	return event
+}
+
+func TestHandlerDedupe(t *testing.T) {
+
+	handler := NewHandler()
+	handler.DedupeStore = NewMemoryReplayCache()
+
+	received := 0
+	handler.OnMessageCreated(func(event *MessageEvent) error {
+		received++
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/chatwoot", strings.NewReader(messageCreatedPayload)))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d", recorder.Code)
+		}
+	}
+
+	if err := handler.Dispatch(messageCreated(43, 9)); err != nil {
+		t.Fatal(err)
+	}
+
+	if received != 2 {
+		t.Fatalf("expected the redelivered message to be dropped, got %d events", received)
+	}
+
+	if key := DedupeKey(&ConversationEvent{Event: chatwootclient.EventConversationStatusChanged}); key != "" {
+		t.Fatalf("expected status changes not to be deduplicated, got key %q", key)
+	}
+
+}
+
+func TestHandlerDedupeRetry(t *testing.T) {
+
+	handler := NewHandler()
+	handler.DedupeStore = NewMemoryReplayCache()
+
+	fail := true
+	received := 0
+	handler.OnMessageCreated(func(event *MessageEvent) error {
+		if fail {
+			return fmt.Errorf("message %d failed", event.ID)
+		}
+		received++
+		return nil
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/chatwoot", strings.NewReader(messageCreatedPayload)))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected the failed delivery to be reported, got status %d", recorder.Code)
+	}
+
+	fail = false
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/chatwoot", strings.NewReader(messageCreatedPayload)))
+
+	if recorder.Code != http.StatusOK || received != 1 {
+		t.Fatalf("expected the retry to be handled, got status %d and %d events", recorder.Code, received)
+	}
+
+}
+
+func TestQueue(t *testing.T) {
+
+	handler := NewHandler()
+	handler.DedupeStore = NewMemoryReplayCache()
+
+	queue := NewQueue(handler)
+	handler.Queue = queue
+
+	var mutex sync.Mutex
+	processed := map[int][]int{}
+	release := make(chan struct{})
+
+	handler.OnMessageCreated(func(event *MessageEvent) error {
+		if event.ID == 11 {
+			// blocks the first conversation until the second one has been processed
+			<-release
+		}
+		mutex.Lock()
+		processed[event.Conversation.ID] = append(processed[event.Conversation.ID], event.ID%10)
+		done := event.Conversation.ID == 2 && len(processed[2]) == 3
+		mutex.Unlock()
+		if done {
+			close(release)
+		}
+		return fmt.Errorf("message %d failed", event.ID)
+	})
+
+	var errorsMutex sync.Mutex
+	failed := 0
+	queue.ErrorHandler = func(event Event, err error) {
+		errorsMutex.Lock()
+		failed++
+		errorsMutex.Unlock()
+	}
+
+	for messageId := 1; messageId <= 3; messageId++ {
+		for conversationId := 1; conversationId <= 2; conversationId++ {
+			if err := queue.Enqueue(messageCreated(conversationId*10+messageId, conversationId)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// the redelivered message is dropped
+	if err := queue.Enqueue(messageCreated(12, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		queue.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the conversations were not processed in parallel")
+	}
+
+	if fmt.Sprint(processed[1]) != "[1 2 3]" || fmt.Sprint(processed[2]) != "[1 2 3]" {
+		t.Fatalf("unexpected order: %v", processed)
+	}
+
+	if failed != 6 {
+		t.Fatalf("expected 6 errors, got %d", failed)
+	}
+
+	if err := queue.Enqueue(messageCreated(14, 1)); err != ErrQueueClosed {
+		t.Fatalf("expected ErrQueueClosed, got %v", err)
+	}
+
+}
+
+func TestQueueRetry(t *testing.T) {
+
+	handler := NewHandler()
+	handler.DedupeStore = NewMemoryReplayCache()
+
+	queue := NewQueue(handler)
+	queue.QueueSize = 1
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var mutex sync.Mutex
+	var received []int
+
+	handler.OnMessageCreated(func(event *MessageEvent) error {
+		if event.ID == 1 {
+			close(started)
+			<-release
+		}
+		mutex.Lock()
+		received = append(received, event.ID)
+		mutex.Unlock()
+		return nil
+	})
+
+	if err := queue.Enqueue(messageCreated(1, 9)); err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+
+	if err := queue.Enqueue(messageCreated(2, 9)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := queue.Enqueue(messageCreated(3, 9)); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	close(release)
+	queue.Close()
+
+	if err := queue.Enqueue(messageCreated(4, 9)); err != ErrQueueClosed {
+		t.Fatalf("expected ErrQueueClosed, got %v", err)
+	}
+
+	// the events that were not enqueued are not deduplicated when they are redelivered
+	for _, messageId := range []int{3, 4} {
+		if err := handler.Dispatch(messageCreated(messageId, 9)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if fmt.Sprint(received) != "[1 2 3 4]" {
+		t.Fatalf("unexpected events: %v", received)
+	}
+
+}
@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers Chatwoot sends with signed webhook requests.
+const (
+	SignatureHeader = "X-Chatwoot-Signature"
+	TimestampHeader = "X-Chatwoot-Timestamp"
+	DeliveryHeader  = "X-Chatwoot-Delivery"
+)
+
+// DefaultTimestampTolerance is the maximum age of a signed request if the Handler has no TimestampTolerance.
+const DefaultTimestampTolerance = 5 * time.Minute
+
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidTimestamp = errors.New("timestamp outside of the tolerance window")
+	ErrReplayed         = errors.New("delivery has already been received")
+)
+
+// Sign returns the signature of the payload as sent by Chatwoot: sha256= followed by the hex encoded HMAC-SHA256 of
+// "{timestamp}.{payload}".
+func Sign(secret string, timestamp string, payload []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether the signature matches the payload and timestamp, using a constant time comparison.
+func VerifySignature(secret string, timestamp string, payload []byte, signature string) bool {
+
+	expected := Sign(secret, timestamp, payload)
+
+	return hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature)))
+}
+
+// ReplayCache remembers the deliveries that have been received. Implementations backed by a shared store like Redis
+// allow to detect replays across multiple instances.
+type ReplayCache interface {
+	// CheckAndStore records the delivery until expiresAt and reports whether it has been recorded before.
+	CheckAndStore(deliveryID string, expiresAt time.Time) (seen bool, err error)
+	// Remove forgets the delivery, so that it is accepted again when a failed delivery is retried.
+	Remove(deliveryID string) error
+}
+
+// MemoryReplayCache is a ReplayCache that keeps the deliveries in memory.
+type MemoryReplayCache struct {
+	mutex      sync.Mutex
+	deliveries map[string]time.Time
+	lastPrune  time.Time
+	now        func() time.Time
+}
+
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{
+		deliveries: map[string]time.Time{},
+		now:        time.Now,
+	}
+}
+
+func (cache *MemoryReplayCache) CheckAndStore(deliveryID string, expiresAt time.Time) (bool, error) {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	now := cache.now()
+
+	if now.Sub(cache.lastPrune) > time.Minute {
+		for id, expiry := range cache.deliveries {
+			if now.After(expiry) {
+				delete(cache.deliveries, id)
+			}
+		}
+		cache.lastPrune = now
+	}
+
+	if expiry, ok := cache.deliveries[deliveryID]; ok && !now.After(expiry) {
+		return true, nil
+	}
+
+	cache.deliveries[deliveryID] = expiresAt
+
+	return false, nil
+}
+
+func (cache *MemoryReplayCache) Remove(deliveryID string) error {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.deliveries, deliveryID)
+
+	return nil
+}
+
+// verify checks the signature, timestamp and delivery of a request if the handler has a secret. It returns the key
+// the delivery has been recorded with in the ReplayCache, which has to be removed if handling the request fails.
+func (handler *Handler) verify(payload []byte, signature string, timestamp string, deliveryID string) (string, error) {
+
+	if handler.Secret == "" {
+		return "", nil
+	}
+
+	if signature == "" || timestamp == "" {
+		return "", ErrMissingSignature
+	}
+
+	if !VerifySignature(handler.Secret, timestamp, payload, signature) {
+		return "", ErrInvalidSignature
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil {
+		return "", ErrInvalidTimestamp
+	}
+
+	tolerance := handler.TimestampTolerance
+	if tolerance == 0 {
+		tolerance = DefaultTimestampTolerance
+	}
+
+	now := time.Now()
+	if handler.now != nil {
+		now = handler.now()
+	}
+	sentAt := time.Unix(seconds, 0)
+
+	if tolerance > 0 && (now.Sub(sentAt) > tolerance || sentAt.Sub(now) > tolerance) {
+		return "", ErrInvalidTimestamp
+	}
+
+	if handler.ReplayCache == nil {
+		return "", nil
+	}
+
+	// the signature is unique per timestamp and payload if Chatwoot sends no delivery id
+	if deliveryID == "" {
+		deliveryID = signature
+	}
+
+	expiresAt := sentAt.Add(tolerance)
+	if tolerance < 0 {
+		expiresAt = now.Add(DefaultTimestampTolerance)
+	}
+
+	seen, err := handler.ReplayCache.CheckAndStore(deliveryID, expiresAt)
+
+	if err != nil {
+		return "", err
+	}
+
+	if seen {
+		return "", ErrReplayed
+	}
+
+	return deliveryID, nil
+}
+
+// forgetDelivery removes the delivery recorded by verify from the ReplayCache, so that Chatwoot can retry it.
+func (handler *Handler) forgetDelivery(deliveryID string) {
+
+	if deliveryID == "" {
+		return
+	}
+
+	// the request fails anyway, if removing fails as well the retry is rejected as replay
+	handler.ReplayCache.Remove(deliveryID)
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedHandler(t *testing.T) {
+
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	handler := NewSignedHandler("secret")
+	handler.now = func() time.Time { return now }
+	handler.ReplayCache.(*MemoryReplayCache).now = handler.now
+
+	received := 0
+	fail := false
+	handler.OnMessageCreated(func(event *MessageEvent) error {
+		if fail {
+			return errors.New("database unavailable")
+		}
+		received++
+		return nil
+	})
+
+	post := func(signature string, sentAt time.Time, deliveryID string) int {
+		request := httptest.NewRequest(http.MethodPost, "/chatwoot", strings.NewReader(messageCreatedPayload))
+		request.Header.Set(SignatureHeader, signature)
+		request.Header.Set(TimestampHeader, strconv.FormatInt(sentAt.Unix(), 10))
+		request.Header.Set(DeliveryHeader, deliveryID)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	sign := func(sentAt time.Time) string {
+		return Sign("secret", strconv.FormatInt(sentAt.Unix(), 10), []byte(messageCreatedPayload))
+	}
+
+	sentAt := now.Add(-time.Minute)
+
+	if status := post(sign(sentAt), sentAt, "delivery-1"); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	if status := post(sign(sentAt), sentAt, "delivery-1"); status != http.StatusConflict {
+		t.Fatalf("expected the replayed delivery to be rejected, got status %d", status)
+	}
+
+	if status := post(Sign("other", strconv.FormatInt(sentAt.Unix(), 10), []byte(messageCreatedPayload)), sentAt, "delivery-2"); status != http.StatusUnauthorized {
+		t.Fatalf("expected the forged signature to be rejected, got status %d", status)
+	}
+
+	// the timestamp is part of the signature, it can not be refreshed without the secret
+	if status := post(sign(sentAt), now, "delivery-3"); status != http.StatusUnauthorized {
+		t.Fatalf("expected the modified timestamp to be rejected, got status %d", status)
+	}
+
+	staleAt := now.Add(-DefaultTimestampTolerance - time.Second)
+
+	if status := post(sign(staleAt), staleAt, "delivery-4"); status != http.StatusUnauthorized {
+		t.Fatalf("expected the stale request to be rejected, got status %d", status)
+	}
+
+	if status := post("", sentAt, "delivery-5"); status != http.StatusUnauthorized {
+		t.Fatalf("expected the unsigned request to be rejected, got status %d", status)
+	}
+
+	fail = true
+
+	if status := post(sign(sentAt), sentAt, "delivery-6"); status != http.StatusInternalServerError {
+		t.Fatalf("expected the failed delivery to be reported, got status %d", status)
+	}
+
+	fail = false
+
+	if status := post(sign(sentAt), sentAt, "delivery-6"); status != http.StatusOK {
+		t.Fatalf("expected the retry of the failed delivery to be accepted, got status %d", status)
+	}
+
+	if received != 2 {
+		t.Fatalf("expected 2 events, got %d", received)
+	}
+
+}
+
+func TestMemoryReplayCache(t *testing.T) {
+
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	cache := NewMemoryReplayCache()
+	cache.now = func() time.Time { return now }
+
+	if seen, _ := cache.CheckAndStore("delivery-1", now.Add(time.Minute)); seen {
+		t.Fatal("expected the first delivery not to be seen")
+	}
+
+	if seen, _ := cache.CheckAndStore("delivery-1", now.Add(time.Minute)); !seen {
+		t.Fatal("expected the second delivery to be seen")
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if seen, _ := cache.CheckAndStore("delivery-1", now.Add(time.Minute)); seen {
+		t.Fatal("expected the expired delivery to be forgotten")
+	}
+
+	if len(cache.deliveries) != 1 {
+		t.Fatalf("expected expired deliveries to be pruned, got %d", len(cache.deliveries))
+	}
+
+	cache.Remove("delivery-1")
+
+	if seen, _ := cache.CheckAndStore("delivery-1", now.Add(time.Minute)); seen {
+		t.Fatal("expected the removed delivery to be forgotten")
+	}
+
+}
@@ -0,0 +1,172 @@
+package chatwootclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// WebhookEvent is the name of an event Chatwoot sends to webhooks.
+type WebhookEvent string
+
+const (
+	EventConversationCreated       WebhookEvent = "conversation_created"
+	EventConversationStatusChanged WebhookEvent = "conversation_status_changed"
+	EventConversationUpdated       WebhookEvent = "conversation_updated"
+	EventConversationTypingOn      WebhookEvent = "conversation_typing_on"
+	EventConversationTypingOff     WebhookEvent = "conversation_typing_off"
+	EventContactCreated            WebhookEvent = "contact_created"
+	EventContactUpdated            WebhookEvent = "contact_updated"
+	EventMessageCreated            WebhookEvent = "message_created"
+	EventMessageUpdated            WebhookEvent = "message_updated"
+	EventWebwidgetTriggered        WebhookEvent = "webwidget_triggered"
+
+	// only sent to agent bots
+	EventConversationOpened   WebhookEvent = "conversation_opened"
+	EventConversationResolved WebhookEvent = "conversation_resolved"
+)
+
+type Webhook struct {
+	ID            int            `json:"id"`
+	AccountID     int            `json:"account_id,omitempty"`
+	Url           string         `json:"url"`
+	Subscriptions []WebhookEvent `json:"subscriptions"`
+}
+
+type WebhookRequest struct {
+	Url           string         `json:"url"`
+	Subscriptions []WebhookEvent `json:"subscriptions"`
+}
+
+type ListWebhooksResponse struct {
+	Payload struct {
+		Webhooks []Webhook `json:"webhooks"`
+	} `json:"payload"`
+}
+
+type WebhookResponse struct {
+	Payload struct {
+		Webhook Webhook `json:"webhook"`
+	} `json:"payload"`
+}
+
+func (client *ChatwootClient) ListWebhooks(accountId int64, agentToken string) ([]Webhook, error) {
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/webhooks", client.BaseUrl, accountId)
+
+	var listWebhooksResponse ListWebhooksResponse
+
+	if err := client.doJSONRequest(http.MethodGet, requestURL, agentToken, nil, &listWebhooksResponse); err != nil {
+		return nil, err
+	}
+
+	return listWebhooksResponse.Payload.Webhooks, nil
+}
+
+func (client *ChatwootClient) CreateWebhook(accountId int64, agentToken string, webhookRequest WebhookRequest) (Webhook, error) {
+
+	if agentToken == "" {
+		return Webhook{}, errors.New("agentToken is empty. Creating webhooks requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/webhooks", client.BaseUrl, accountId)
+
+	var webhookResponse WebhookResponse
+
+	if err := client.doJSONRequest(http.MethodPost, requestURL, agentToken, webhookRequest, &webhookResponse); err != nil {
+		return Webhook{}, err
+	}
+
+	return webhookResponse.Payload.Webhook, nil
+}
+
+func (client *ChatwootClient) UpdateWebhook(accountId int64, webhookId int64, agentToken string, webhookRequest WebhookRequest) (Webhook, error) {
+
+	if agentToken == "" {
+		return Webhook{}, errors.New("agentToken is empty. Updating webhooks requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/webhooks/%v", client.BaseUrl, accountId, webhookId)
+
+	var webhookResponse WebhookResponse
+
+	if err := client.doJSONRequest(http.MethodPatch, requestURL, agentToken, webhookRequest, &webhookResponse); err != nil {
+		return Webhook{}, err
+	}
+
+	return webhookResponse.Payload.Webhook, nil
+}
+
+func (client *ChatwootClient) DeleteWebhook(accountId int64, webhookId int64, agentToken string) error {
+
+	if agentToken == "" {
+		return errors.New("agentToken is empty. Deleting webhooks requires a Chatwoot agent token")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v1/accounts/%v/webhooks/%v", client.BaseUrl, accountId, webhookId)
+
+	return client.doJSONRequest(http.MethodDelete, requestURL, agentToken, nil, nil)
+}
+
+// EnsureWebhook makes sure that a webhook for the url exists that is subscribed to exactly the given events. The
+// webhook is created if it does not exist and updated if its subscriptions differ, so it can be called on every
+// startup.
+func (client *ChatwootClient) EnsureWebhook(accountId int64, agentToken string, url string, subscriptions []WebhookEvent) (Webhook, error) {
+
+	webhooks, err := client.ListWebhooks(accountId, agentToken)
+
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	webhookRequest := WebhookRequest{
+		Url:           url,
+		Subscriptions: subscriptions,
+	}
+
+	for _, webhook := range webhooks {
+
+		if webhook.Url != url {
+			continue
+		}
+
+		if sameWebhookEvents(webhook.Subscriptions, subscriptions) {
+			return webhook, nil
+		}
+
+		return client.UpdateWebhook(accountId, int64(webhook.ID), agentToken, webhookRequest)
+	}
+
+	return client.CreateWebhook(accountId, agentToken, webhookRequest)
+}
+
+func sameWebhookEvents(a []WebhookEvent, b []WebhookEvent) bool {
+
+	normalize := func(events []WebhookEvent) []string {
+		unique := map[WebhookEvent]bool{}
+		for _, event := range events {
+			unique[event] = true
+		}
+		normalized := make([]string, 0, len(unique))
+		for event := range unique {
+			normalized = append(normalized, string(event))
+		}
+		sort.Strings(normalized)
+		return normalized
+	}
+
+	normalizedA, normalizedB := normalize(a), normalize(b)
+
+	if len(normalizedA) != len(normalizedB) {
+		return false
+	}
+
+	for i := range normalizedA {
+		if normalizedA[i] != normalizedB[i] {
+			return false
+		}
+	}
+
+	return true
+}
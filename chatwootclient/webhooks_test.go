@@ -0,0 +1,92 @@
+package chatwootclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnsureWebhook(t *testing.T) {
+
+	webhooks := []Webhook{
+		{ID: 1, Url: "https://staging.example.com/chatwoot", Subscriptions: []WebhookEvent{EventMessageCreated}},
+	}
+	var calls []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		var webhookRequest WebhookRequest
+		json.NewDecoder(r.Body).Decode(&webhookRequest)
+
+		var webhookResponse WebhookResponse
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/accounts/1/webhooks":
+			var listWebhooksResponse ListWebhooksResponse
+			listWebhooksResponse.Payload.Webhooks = webhooks
+			json.NewEncoder(w).Encode(listWebhooksResponse)
+			return
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/accounts/1/webhooks":
+			webhookResponse.Payload.Webhook = Webhook{ID: len(webhooks) + 1, Url: webhookRequest.Url, Subscriptions: webhookRequest.Subscriptions}
+			webhooks = append(webhooks, webhookResponse.Payload.Webhook)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v1/accounts/1/webhooks/2":
+			webhooks[1].Subscriptions = webhookRequest.Subscriptions
+			webhookResponse.Payload.Webhook = webhooks[1]
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(webhookResponse)
+
+	}))
+
+	defer server.Close()
+
+	client := ChatwootClient{
+		BaseUrl: server.URL,
+	}
+
+	url := "https://production.example.com/chatwoot"
+
+	// created
+	webhook, err := client.EnsureWebhook(1, "agent-token", url, []WebhookEvent{EventMessageCreated, EventConversationStatusChanged})
+
+	if err != nil || webhook.ID != 2 {
+		t.Fatalf("unexpected webhook %+v: %v", webhook, err)
+	}
+
+	// unchanged
+	webhook, err = client.EnsureWebhook(1, "agent-token", url, []WebhookEvent{EventConversationStatusChanged, EventMessageCreated})
+
+	if err != nil || webhook.ID != 2 {
+		t.Fatalf("unexpected webhook %+v: %v", webhook, err)
+	}
+
+	// updated
+	webhook, err = client.EnsureWebhook(1, "agent-token", url, []WebhookEvent{EventMessageCreated})
+
+	if err != nil || webhook.ID != 2 || len(webhook.Subscriptions) != 1 {
+		t.Fatalf("unexpected webhook %+v: %v", webhook, err)
+	}
+
+	expected := []string{
+		"GET /api/v1/accounts/1/webhooks", "POST /api/v1/accounts/1/webhooks",
+		"GET /api/v1/accounts/1/webhooks",
+		"GET /api/v1/accounts/1/webhooks", "PATCH /api/v1/accounts/1/webhooks/2",
+	}
+
+	if len(calls) != len(expected) {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("unexpected calls: %v", calls)
+		}
+	}
+
+}
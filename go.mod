@@ -1,3 +1,5 @@
 module github.com/ga-commerce/chatwoot-golang-client
 
 go 1.19
+
+require github.com/gorilla/websocket v1.5.3